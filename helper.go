@@ -2,6 +2,7 @@ package thrift
 
 import (
 	"testing"
+	"time"
 
 	"github.com/apache/thrift/lib/go/thrift"
 )
@@ -31,7 +32,7 @@ func assertTrue(t *testing.T, title string, result bool) {
 	assert(t, title, result, true)
 }
 
-func assert[T string | bool | int | int16 | int32 | thrift.TType](t *testing.T, title string, actual, expected T) {
+func assert[T string | bool | int | int8 | int16 | int32 | int64 | uint16 | float64 | thrift.TType | time.Duration](t *testing.T, title string, actual, expected T) {
 	if actual != expected {
 		t.Fatalf("[%v] Expected %v but was %v", title, expected, actual)
 	}
@@ -0,0 +1,55 @@
+package it
+
+import (
+	"testing"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	xk6_thrift "github.com/lavenderses/xk6-thrift"
+	"go.k6.io/k6/js/modulestest"
+)
+
+func TestClientCallAsync(t *testing.T) {
+	// prepare
+	rt := modulestest.NewRuntime(t)
+	tf := thrift.NewTFramedTransportFactoryConf(thrift.NewTTransportFactory(), nil)
+	addr, service := startBarrierServer(t, tf, thrift.NewTBinaryProtocolFactoryConf(nil), 3)
+	module := new(xk6_thrift.TRootModule).NewModuleInstance(rt.VU).(*xk6_thrift.TModule)
+	client, err := module.NewClient(xk6_thrift.TClientOptions{URL: "tcp://" + addr, Transport: "framed"})
+	if err != nil {
+		t.Fatalf("error creating client. %v", err)
+	}
+	t.Cleanup(client.Close)
+	if err = rt.VU.Runtime().Set("client", client); err != nil {
+		t.Fatal(err)
+	}
+	if err = rt.VU.Runtime().Set("req", simpleCallRequest("ID")); err != nil {
+		t.Fatal(err)
+	}
+
+	// do
+	_, err = rt.RunOnEventLoop(`
+		Promise.all([
+			client.callAsync("simpleCall", req),
+			client.callAsync("simpleCall", req),
+			client.callAsync("simpleCall", req),
+		]).then((rs) => { globalThis.results = rs; });
+	`)
+
+	// verify
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := rt.VU.Runtime().Get("results").Export().([]any)
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, but was %d", len(results))
+	}
+	for i, r := range results {
+		if res := r.(*xk6_thrift.TCallResult); !res.IsSuccess() {
+			t.Errorf("expected success of #%d, but failed. %v", i, res)
+		}
+	}
+	// calls are made in parallel with their own connections
+	if actual := service.maxInFlight.Load(); actual != 3 {
+		t.Errorf("expected 3 calls in flight at the same time, but was %d", actual)
+	}
+}
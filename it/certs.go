@@ -0,0 +1,117 @@
+package it
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+)
+
+// certificates is PEM encoded certificates and keys for TLS tests, signed by a single CA.
+type certificates struct {
+	CA         string
+	ServerCert string
+	ServerKey  string
+	ClientCert string
+	ClientKey  string
+}
+
+// serverTLSConfig returns TLS config of server. Client certificate is required when mtls is true.
+func (c *certificates) serverTLSConfig(t *testing.T, mtls bool) *tls.Config {
+	cert, err := tls.X509KeyPair([]byte(c.ServerCert), []byte(c.ServerKey))
+	if err != nil {
+		t.Fatalf("error loading server certificate. %v", err)
+	}
+	cfg := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	if mtls {
+		pool := x509.NewCertPool()
+		pool.AppendCertsFromPEM([]byte(c.CA))
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return cfg
+}
+
+// newCertificates generates CA, server certificate for `localhost` and 127.0.0.1, and client certificate.
+func newCertificates(t *testing.T) *certificates {
+	caKey := newKey(t)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "xk6-thrift test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("error creating CA certificate. %v", err)
+	}
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatalf("error parsing CA certificate. %v", err)
+	}
+
+	serverKey := newKey(t)
+	serverCert := signCertificate(t, ca, caKey, serverKey, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+
+	clientKey := newKey(t)
+	clientCert := signCertificate(t, ca, caKey, clientKey, &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "xk6-thrift client"},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+
+	return &certificates{
+		CA:         encodePEM("CERTIFICATE", caDER),
+		ServerCert: serverCert,
+		ServerKey:  encodeKey(t, serverKey),
+		ClientCert: clientCert,
+		ClientKey:  encodeKey(t, clientKey),
+	}
+}
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("error generating key. %v", err)
+	}
+	return key
+}
+
+func signCertificate(t *testing.T, ca *x509.Certificate, caKey, key *ecdsa.PrivateKey, template *x509.Certificate) string {
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("error creating certificate. %v", err)
+	}
+	return encodePEM("CERTIFICATE", der)
+}
+
+func encodeKey(t *testing.T, key *ecdsa.PrivateKey) string {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("error encoding key. %v", err)
+	}
+	return encodePEM("EC PRIVATE KEY", der)
+}
+
+func encodePEM(typ string, der []byte) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}))
+}
@@ -0,0 +1,243 @@
+package it
+
+import (
+	"testing"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	xk6_thrift "github.com/lavenderses/xk6-thrift"
+)
+
+func newClient(t *testing.T, opts xk6_thrift.TClientOptions) *xk6_thrift.TClient {
+	client, err := xk6_thrift.NewTClient(&opts)
+	if err != nil {
+		t.Fatalf("error creating client. %v", err)
+	}
+	// close persistent connections before server stops
+	t.Cleanup(client.Close)
+	return client
+}
+
+func simpleCallRequest(id string) *xk6_thrift.TRequest {
+	values := map[int16]xk6_thrift.TValue{
+		1: xk6_thrift.NewTstring(id),
+	}
+	return xk6_thrift.NewTRequestWithValue(&values)
+}
+
+func messageCallRequest() *xk6_thrift.TRequest {
+	value := map[xk6_thrift.TStructField]xk6_thrift.TValue{
+		*xk6_thrift.NewTStructField(1, "content"): xk6_thrift.NewTstring("this is a content"),
+		*xk6_thrift.NewTStructField(2, "tags"): xk6_thrift.NewTMap(
+			thrift.STRING,
+			thrift.BOOL,
+			&map[xk6_thrift.TValue]xk6_thrift.TValue{
+				xk6_thrift.NewTstring("bool true"): xk6_thrift.NewTBool(true),
+			},
+		),
+		*xk6_thrift.NewTStructField(3, "nested"): xk6_thrift.NewTStruct(
+			&map[xk6_thrift.TStructField]xk6_thrift.TValue{
+				*xk6_thrift.NewTStructField(1, "inner"): xk6_thrift.NewTstring("this is an inner content"),
+			},
+		),
+	}
+	tvalue := map[int16]xk6_thrift.TValue{
+		1: xk6_thrift.NewTStruct(&value),
+	}
+	return xk6_thrift.NewTRequestWithValue(&tvalue)
+}
+
+func TestClientSocketTransports(t *testing.T) {
+	cases := map[string]thrift.TTransportFactory{
+		"socket":   thrift.NewTTransportFactory(),
+		"framed":   thrift.NewTFramedTransportFactoryConf(thrift.NewTTransportFactory(), nil),
+		"buffered": thrift.NewTBufferedTransportFactory(8_192),
+	}
+
+	for transport, tf := range cases {
+		t.Run(transport, func(t *testing.T) {
+			// prepare
+			addr := startServer(t, tf, thrift.NewTBinaryProtocolFactoryConf(nil))
+			client := newClient(t, xk6_thrift.TClientOptions{
+				URL:       "tcp://" + addr,
+				Transport: transport,
+			})
+
+			// do
+			success := client.Call("simpleCall", simpleCallRequest("ID"), nil)
+			failure := client.Call("simpleCall", simpleCallRequest("FAILURE"), nil)
+
+			// verify
+			if !success.IsSuccess() {
+				t.Errorf("expected success, but failed. %v", success)
+			}
+			if failure.IsSuccess() {
+				t.Errorf("expected failure, but succeeded. %v", failure)
+			}
+		})
+	}
+}
+
+func TestClientSocketTransport_mismatch(t *testing.T) {
+	// prepare
+	tf := thrift.NewTFramedTransportFactoryConf(thrift.NewTTransportFactory(), nil)
+	addr := startServer(t, tf, thrift.NewTBinaryProtocolFactoryConf(nil))
+	client := newClient(t, xk6_thrift.TClientOptions{
+		URL:       "tcp://" + addr,
+		Transport: "buffered",
+		Timeout:   "1s",
+	})
+
+	// do
+	res := client.Call("simpleCall", simpleCallRequest("ID"), nil)
+
+	// verify
+	if res.IsSuccess() {
+		t.Errorf("expected failure with unframed client against framed server, but succeeded.")
+	}
+}
+
+func TestClientProtocols(t *testing.T) {
+	cases := map[string]thrift.TProtocolFactory{
+		"binary":  thrift.NewTBinaryProtocolFactoryConf(nil),
+		"compact": thrift.NewTCompactProtocolFactoryConf(nil),
+		"json":    thrift.NewTJSONProtocolFactory(),
+	}
+
+	for protocol, pf := range cases {
+		t.Run(protocol, func(t *testing.T) {
+			// prepare
+			tf := thrift.NewTFramedTransportFactoryConf(thrift.NewTTransportFactory(), nil)
+			addr := startServer(t, tf, pf)
+			client := newClient(t, xk6_thrift.TClientOptions{
+				URL:       "tcp://" + addr,
+				Protocol:  protocol,
+				Transport: "framed",
+			})
+
+			// do
+			simple := client.Call("simpleCall", simpleCallRequest("ID"), nil)
+			message := client.Call("messageCall", messageCallRequest(), nil)
+
+			// verify
+			if !simple.IsSuccess() {
+				t.Errorf("expected success of simpleCall, but failed. %v", simple)
+			}
+			if !message.IsSuccess() {
+				t.Errorf("expected success of messageCall, but failed. %v", message)
+			}
+		})
+	}
+}
+
+func TestClientProtocols_http(t *testing.T) {
+	cases := map[string]struct {
+		pf          thrift.TProtocolFactory
+		contentType string
+	}{
+		"binary":  {thrift.NewTBinaryProtocolFactoryConf(nil), "application/x-thrift; protocol=TBINARY"},
+		"compact": {thrift.NewTCompactProtocolFactoryConf(nil), "application/x-thrift; protocol=TCOMPACT"},
+		"json":    {thrift.NewTJSONProtocolFactory(), "application/x-thrift; protocol=TJSON"},
+	}
+
+	for protocol, c := range cases {
+		t.Run(protocol, func(t *testing.T) {
+			// prepare
+			server := startHTTPServer(t, c.pf)
+			client := newClient(t, xk6_thrift.TClientOptions{
+				URL:      server.URL,
+				Protocol: protocol,
+			})
+
+			// do
+			res := client.Call("messageCall", messageCallRequest(), nil)
+
+			// verify
+			if !res.IsSuccess() {
+				t.Errorf("expected success, but failed. %v", res)
+			}
+			contentTypes := server.ContentTypes()
+			if len(contentTypes) != 1 || contentTypes[0] != c.contentType {
+				t.Errorf("expected Content-Type %q, but was %v", c.contentType, contentTypes)
+			}
+		})
+	}
+}
+
+func TestClientMultiplexed(t *testing.T) {
+	// prepare
+	tf := thrift.NewTFramedTransportFactoryConf(thrift.NewTTransportFactory(), nil)
+	addr := startMultiplexedServer(t, tf, thrift.NewTBinaryProtocolFactoryConf(nil), "UserService", "OrderService")
+	client := newClient(t, xk6_thrift.TClientOptions{
+		URL:       "tcp://" + addr,
+		Transport: "framed",
+		Service:   "UserService",
+	})
+	cases := map[string]struct {
+		opts    *xk6_thrift.TCallOptions
+		success bool
+	}{
+		"client service":       {nil, true},
+		"call service":         {&xk6_thrift.TCallOptions{Service: "OrderService"}, true},
+		"unknown call service": {&xk6_thrift.TCallOptions{Service: "UnknownService"}, false},
+	}
+
+	for title, c := range cases {
+		// do
+		res := client.Call("simpleCall", simpleCallRequest("ID"), c.opts)
+
+		// verify
+		if res.IsSuccess() != c.success {
+			t.Errorf("[%s] expected success=%v, but was %v", title, c.success, res)
+		}
+	}
+}
+
+func TestClientMultiplexed_withoutService(t *testing.T) {
+	// prepare
+	tf := thrift.NewTFramedTransportFactoryConf(thrift.NewTTransportFactory(), nil)
+	addr := startMultiplexedServer(t, tf, thrift.NewTBinaryProtocolFactoryConf(nil), "UserService")
+	client := newClient(t, xk6_thrift.TClientOptions{
+		URL:       "tcp://" + addr,
+		Transport: "framed",
+	})
+
+	// do
+	res := client.Call("simpleCall", simpleCallRequest("ID"), nil)
+
+	// verify
+	if res.IsSuccess() {
+		t.Errorf("expected failure without service name, but succeeded.")
+	}
+}
+
+func TestClientResult(t *testing.T) {
+	// prepare
+	tf := thrift.NewTFramedTransportFactoryConf(thrift.NewTTransportFactory(), nil)
+	addr := startServer(t, tf, thrift.NewTBinaryProtocolFactoryConf(nil))
+	client := newClient(t, xk6_thrift.TClientOptions{URL: "tcp://" + addr, Transport: "framed"})
+
+	// do
+	first := client.Call("messageCall", messageCallRequest(), nil)
+	second := client.Call("messageCall", messageCallRequest(), nil)
+
+	// verify
+	if !first.IsSuccess() {
+		t.Fatalf("expected success, but failed. %v", first)
+	}
+	body, ok := first.Body().(map[string]any)
+	if !ok {
+		t.Fatalf("expected struct body, but was %T", first.Body())
+	}
+	if body["1"] != "content: this is a content" {
+		t.Errorf("unexpected content %v", body["1"])
+	}
+	if nested := body["3"].(map[string]any); nested["1"] != "this is an inner content" {
+		t.Errorf("unexpected nested %v", nested)
+	}
+	if first.Method() != "messageCall" || first.Duration() <= 0 {
+		t.Errorf("unexpected method or duration %s %v", first.Method(), first.Duration())
+	}
+	if second.SeqId() != first.SeqId()+1 {
+		t.Errorf("expected sequence ID incremented, but was %d and %d", first.SeqId(), second.SeqId())
+	}
+}
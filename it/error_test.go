@@ -0,0 +1,54 @@
+package it
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	xk6_thrift "github.com/lavenderses/xk6-thrift"
+)
+
+func TestClientError_httpStatus(t *testing.T) {
+	// prepare
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+	client := newClient(t, xk6_thrift.TClientOptions{URL: server.URL})
+
+	// do
+	actual := client.Call("simpleCall", simpleCallRequest("ID"), nil)
+
+	// verify
+	if actual.IsSuccess() {
+		t.Fatalf("expected failure, but succeeded. %v", actual)
+	}
+	if actual.ErrorKind() != "http_status" || actual.ErrorCode() != "503" {
+		t.Errorf("expected http_status 503, but was %q %q", actual.ErrorKind(), actual.ErrorCode())
+	}
+	if actual.Status() != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, but was %d", actual.Status())
+	}
+}
+
+func TestClientError_notOpen(t *testing.T) {
+	cases := map[string]xk6_thrift.TClientOptions{
+		"framed": {URL: "tcp://127.0.0.1:1", Transport: "framed"},
+		"http":   {URL: "http://127.0.0.1:1/thrift"},
+	}
+
+	for title, opts := range cases {
+		t.Run(title, func(t *testing.T) {
+			// prepare
+			client := newClient(t, opts)
+
+			// do
+			actual := client.Call("simpleCall", simpleCallRequest("ID"), nil)
+
+			// verify
+			if actual.ErrorKind() != "transport" || actual.ErrorCode() != "NOT_OPEN" {
+				t.Errorf("expected transport NOT_OPEN, but was %q %q", actual.ErrorKind(), actual.ErrorCode())
+			}
+		})
+	}
+}
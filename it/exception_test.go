@@ -0,0 +1,79 @@
+package it
+
+import (
+	"testing"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	xk6_thrift "github.com/lavenderses/xk6-thrift"
+)
+
+func TestClientException_declared(t *testing.T) {
+	// prepare
+	tf := thrift.NewTFramedTransportFactoryConf(thrift.NewTTransportFactory(), nil)
+	addr, _ := startExtendedServer(t, tf, thrift.NewTBinaryProtocolFactoryConf(nil))
+	client := newClient(t, xk6_thrift.TClientOptions{URL: "tcp://" + addr, Transport: "framed"})
+
+	// do
+	actual := client.Call("throw", simpleCallRequest("ID"), nil)
+
+	// verify
+	if actual.IsSuccess() {
+		t.Fatalf("expected exception, but succeeded. %v", actual)
+	}
+	exception := actual.Exception()
+	if exception == nil || exception.Kind != "declared" || exception.ID != 1 {
+		t.Fatalf("expected declared exception 1, but was %+v", exception)
+	}
+	if actual.ErrorKind() != "declared_exception" || actual.ErrorCode() != "1" {
+		t.Errorf("expected declared_exception 1, but was %q %q", actual.ErrorKind(), actual.ErrorCode())
+	}
+	expected := xk6_thrift.TValue(xk6_thrift.NewTStruct(&map[xk6_thrift.TStructField]xk6_thrift.TValue{
+		*xk6_thrift.NewTStructField(1, ""): xk6_thrift.NewTstring("not found: ID"),
+	}))
+	if !exception.Value.Equals(&expected) {
+		t.Errorf("expected %v, but was %v", expected, exception.Value)
+	}
+	if value, ok := exception.JSValue.(map[string]any); !ok || value["1"] != "not found: ID" {
+		t.Errorf("expected exported exception, but was %v", exception.JSValue)
+	}
+	// connection is still usable
+	if res := client.Call("simpleCall", simpleCallRequest("ID"), nil); !res.IsSuccess() {
+		t.Errorf("expected success after exception, but failed. %v", res)
+	}
+}
+
+func TestClientException_application(t *testing.T) {
+	cases := map[string]struct {
+		method   string
+		id       string
+		expected int32
+		code     string
+	}{
+		"unknown method": {"unknown", "ID", thrift.UNKNOWN_METHOD, "UNKNOWN_METHOD"},
+		"internal error": {"simpleCall", "FAILURE", thrift.INTERNAL_ERROR, "INTERNAL_ERROR"},
+	}
+
+	for title, c := range cases {
+		t.Run(title, func(t *testing.T) {
+			// prepare
+			tf := thrift.NewTFramedTransportFactoryConf(thrift.NewTTransportFactory(), nil)
+			addr := startServer(t, tf, thrift.NewTBinaryProtocolFactoryConf(nil))
+			client := newClient(t, xk6_thrift.TClientOptions{URL: "tcp://" + addr, Transport: "framed"})
+
+			// do
+			actual := client.Call(c.method, simpleCallRequest(c.id), nil)
+
+			// verify
+			exception := actual.Exception()
+			if exception == nil || exception.Kind != "application" || exception.Type != c.expected {
+				t.Fatalf("expected application exception %d, but was %+v", c.expected, exception)
+			}
+			if exception.Message == "" {
+				t.Error("expected message, but was empty")
+			}
+			if actual.ErrorKind() != "application" || actual.ErrorCode() != c.code {
+				t.Errorf("expected application %s, but was %q %q", c.code, actual.ErrorKind(), actual.ErrorCode())
+			}
+		})
+	}
+}
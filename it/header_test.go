@@ -0,0 +1,110 @@
+package it
+
+import (
+	"testing"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	xk6_thrift "github.com/lavenderses/xk6-thrift"
+)
+
+func TestClientHeader(t *testing.T) {
+	for _, transport := range []string{"socket", "buffered"} {
+		t.Run(transport, func(t *testing.T) {
+			// prepare
+			addr := startHeaderServer(t)
+			client := newClient(t, xk6_thrift.TClientOptions{
+				URL:       "tcp://" + addr,
+				Protocol:  "header",
+				Transport: transport,
+				Headers: map[string]string{
+					"client-name": "k6",
+					"request-id":  "client",
+				},
+			})
+			opts := &xk6_thrift.TCallOptions{
+				Headers: map[string]string{"request-id": "call"},
+			}
+
+			// do
+			res := client.Call("simpleCall", simpleCallRequest("ID"), opts)
+
+			// verify
+			if !res.IsSuccess() {
+				t.Fatalf("expected success, but failed. %v", res)
+			}
+			headers := res.Headers()
+			if headers["client-name"] != "k6" {
+				t.Errorf("expected client-name header k6, but was %v", headers)
+			}
+			if headers["request-id"] != "call" {
+				t.Errorf("expected request-id header call, but was %v", headers)
+			}
+		})
+	}
+}
+
+func TestClientHeader_multiplexed(t *testing.T) {
+	// prepare
+	addr := startHeaderServer(t, "UserService")
+	client := newClient(t, xk6_thrift.TClientOptions{
+		URL:       "tcp://" + addr,
+		Protocol:  "header",
+		Transport: "socket",
+		Service:   "UserService",
+		Headers:   map[string]string{"request-id": "client"},
+	})
+
+	// do
+	res := client.Call("simpleCall", simpleCallRequest("ID"), nil)
+
+	// verify
+	if !res.IsSuccess() {
+		t.Fatalf("expected success, but failed. %v", res)
+	}
+	if res.Headers()["request-id"] != "client" {
+		t.Errorf("expected request-id header client, but was %v", res.Headers())
+	}
+}
+
+func TestClientHeader_http(t *testing.T) {
+	// prepare
+	server := startHTTPServer(t, thrift.NewTBinaryProtocolFactoryConf(nil))
+	client := newClient(t, xk6_thrift.TClientOptions{
+		URL: server.URL,
+		Headers: map[string]string{
+			"Authorization": "Bearer token",
+			"X-Request-Id":  "client",
+		},
+	})
+	opts := &xk6_thrift.TCallOptions{
+		Headers: map[string]string{"X-Request-Id": "call"},
+	}
+
+	// do
+	first := client.Call("simpleCall", simpleCallRequest("ID"), opts)
+	second := client.Call("simpleCall", simpleCallRequest("ID"), nil)
+
+	// verify
+	for _, res := range []*xk6_thrift.TCallResult{first, second} {
+		if !res.IsSuccess() {
+			t.Fatalf("expected success, but failed. %v", res)
+		}
+		if res.Status() != 200 {
+			t.Errorf("expected status 200, but was %d", res.Status())
+		}
+	}
+	headers := server.Headers()
+	if len(headers) != 2 {
+		t.Fatalf("expected 2 requests, but was %d", len(headers))
+	}
+	if headers[0].Get("Authorization") != "Bearer token" || headers[1].Get("Authorization") != "Bearer token" {
+		t.Errorf("expected Authorization header in every request, but was %v", headers)
+	}
+	// per-call headers are not sent by the next call
+	if headers[0].Get("X-Request-Id") != "call" || headers[1].Get("X-Request-Id") != "client" {
+		t.Errorf("expected X-Request-Id header call and client, but was %v", headers)
+	}
+	if first.Headers()["X-Request-Id"] != "call" || second.Headers()["X-Request-Id"] != "client" {
+		t.Errorf("expected X-Request-Id response header call and client, but was %v %v", first.Headers(), second.Headers())
+	}
+}
@@ -0,0 +1,87 @@
+package it
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	xk6_thrift "github.com/lavenderses/xk6-thrift"
+)
+
+func enumCallRequest() *xk6_thrift.TRequest {
+	values := map[int16]xk6_thrift.TValue{
+		1: xk6_thrift.NewTEnum(1),
+	}
+	return xk6_thrift.NewTRequestWithValue(&values)
+}
+
+func TestClientResponseHint(t *testing.T) {
+	features := &xk6_thrift.TTypeHint{
+		Type: "list",
+		Elem: &xk6_thrift.TTypeHint{Type: "enum", Enum: map[int32]string{1: "ONE", 2: "TWO"}},
+	}
+	cases := map[string]struct {
+		responses map[string]*xk6_thrift.TTypeHint
+		callOpts  *xk6_thrift.TCallOptions
+		expected  any
+	}{
+		"no hint": {nil, nil, []any{int32(1), int32(2), int32(3)}},
+		"client hint": {
+			map[string]*xk6_thrift.TTypeHint{"enumCall": features},
+			nil,
+			[]any{
+				map[string]any{"value": int32(1), "name": "ONE"},
+				map[string]any{"value": int32(2), "name": "TWO"},
+				map[string]any{"value": int32(3), "name": ""},
+			},
+		},
+		"call hint": {
+			map[string]*xk6_thrift.TTypeHint{"enumCall": features},
+			&xk6_thrift.TCallOptions{Response: &xk6_thrift.TTypeHint{Type: "list", Elem: &xk6_thrift.TTypeHint{Type: "i32"}}},
+			[]any{int32(1), int32(2), int32(3)},
+		},
+		"mismatched hint": {
+			map[string]*xk6_thrift.TTypeHint{"enumCall": {Type: "list", Elem: &xk6_thrift.TTypeHint{Type: "string"}}},
+			nil,
+			[]any{int32(1), int32(2), int32(3)},
+		},
+	}
+
+	for title, c := range cases {
+		t.Run(title, func(t *testing.T) {
+			// prepare
+			tf := thrift.NewTFramedTransportFactoryConf(thrift.NewTTransportFactory(), nil)
+			addr := startServer(t, tf, thrift.NewTBinaryProtocolFactoryConf(nil))
+			client := newClient(t, xk6_thrift.TClientOptions{
+				URL:       "tcp://" + addr,
+				Transport: "framed",
+				Responses: c.responses,
+			})
+
+			// do
+			actual := client.Call("enumCall", enumCallRequest(), c.callOpts)
+
+			// verify
+			if !actual.IsSuccess() {
+				t.Fatalf("expected success, but failed. %v", actual)
+			}
+			if !reflect.DeepEqual(actual.Body(), c.expected) {
+				t.Errorf("expected %v, but was %v", c.expected, actual.Body())
+			}
+		})
+	}
+}
+
+func TestClientResponseHint_invalid(t *testing.T) {
+	// do
+	_, err := xk6_thrift.NewTClient(&xk6_thrift.TClientOptions{
+		URL:       "tcp://127.0.0.1:9090",
+		Transport: "framed",
+		Responses: map[string]*xk6_thrift.TTypeHint{"enumCall": {Type: "list", Elem: &xk6_thrift.TTypeHint{Type: "integer"}}},
+	})
+
+	// verify
+	if err == nil {
+		t.Fatal("expected error, but succeeded")
+	}
+}
@@ -0,0 +1,77 @@
+package it
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	xk6_thrift "github.com/lavenderses/xk6-thrift"
+)
+
+func TestClientHTTP_h2c(t *testing.T) {
+	// prepare
+	server := startH2CServer(t, thrift.NewTBinaryProtocolFactoryConf(nil))
+	client := newClient(t, xk6_thrift.TClientOptions{
+		URL:  server.URL,
+		HTTP: xk6_thrift.THTTPOptions{H2C: true},
+	})
+
+	// do
+	for range 3 {
+		if res := client.Call("simpleCall", simpleCallRequest("ID"), nil); !res.IsSuccess() {
+			t.Fatalf("expected success, but failed. %v", res)
+		}
+	}
+
+	// verify
+	for _, proto := range server.Protos() {
+		if proto != "HTTP/2.0" {
+			t.Errorf("expected HTTP/2.0, but was %v", server.Protos())
+		}
+	}
+	if actual := server.Connections.Load(); actual != 1 {
+		t.Errorf("expected 1 connection, but was %d", actual)
+	}
+}
+
+func TestClientHTTP_disableKeepAlives(t *testing.T) {
+	// prepare
+	server := startHTTPServer(t, thrift.NewTBinaryProtocolFactoryConf(nil))
+	client := newClient(t, xk6_thrift.TClientOptions{
+		URL:  server.URL,
+		HTTP: xk6_thrift.THTTPOptions{DisableKeepAlives: true},
+	})
+
+	// do
+	for range 3 {
+		if res := client.Call("simpleCall", simpleCallRequest("ID"), nil); !res.IsSuccess() {
+			t.Fatalf("expected success, but failed. %v", res)
+		}
+	}
+
+	// verify
+	if actual := server.Connections.Load(); actual != 3 {
+		t.Errorf("expected 3 connections, but was %d", actual)
+	}
+}
+
+func TestClientHTTP_proxy(t *testing.T) {
+	// prepare
+	// the server handles requests to any host, so it works as a proxy to itself
+	server := startHTTPServer(t, thrift.NewTBinaryProtocolFactoryConf(nil))
+	client := newClient(t, xk6_thrift.TClientOptions{
+		URL:  "http://thrift.invalid/thrift",
+		HTTP: xk6_thrift.THTTPOptions{Proxy: strings.TrimSuffix(server.URL, "/thrift")},
+	})
+
+	// do
+	actual := client.Call("simpleCall", simpleCallRequest("ID"), nil)
+
+	// verify
+	if !actual.IsSuccess() {
+		t.Fatalf("expected success through proxy, but failed. %v", actual)
+	}
+	if len(server.Protos()) != 1 {
+		t.Errorf("expected 1 request to proxy, but was %d", len(server.Protos()))
+	}
+}
@@ -0,0 +1,211 @@
+package it
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	xk6_thrift "github.com/lavenderses/xk6-thrift"
+	"go.k6.io/k6/js/modulestest"
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/metrics"
+)
+
+// newVUClient creates a client in VU context of k6, and returns it with a channel receiving metrics pushed in the VU.
+func newVUClient(t *testing.T, opts xk6_thrift.TClientOptions) (*xk6_thrift.TClient, chan metrics.SampleContainer) {
+	return newVUClientWithState(t, opts, &lib.State{})
+}
+
+// newVUClientWithState is the same as newVUClient, but the VU has state such as Dialer and TLSConfig.
+func newVUClientWithState(
+	t *testing.T, opts xk6_thrift.TClientOptions, state *lib.State,
+) (*xk6_thrift.TClient, chan metrics.SampleContainer) {
+	rt := modulestest.NewRuntime(t)
+	module := new(xk6_thrift.TRootModule).NewModuleInstance(rt.VU).(*xk6_thrift.TModule)
+	client, err := module.NewClient(opts)
+	if err != nil {
+		t.Fatalf("error creating client. %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	samples := make(chan metrics.SampleContainer, 100)
+	state.Samples = samples
+	state.Tags = lib.NewVUStateTags(metrics.NewRegistry().RootTagSet())
+	state.BuiltinMetrics = rt.BuiltinMetrics
+	rt.MoveToVUContext(state)
+	return client, samples
+}
+
+// collectSamples returns all samples pushed so far, grouped by metric name.
+func collectSamples(samples chan metrics.SampleContainer) map[string][]metrics.Sample {
+	collected := map[string][]metrics.Sample{}
+	for {
+		select {
+		case container := <-samples:
+			for _, s := range container.GetSamples() {
+				collected[s.Metric.Name] = append(collected[s.Metric.Name], s)
+			}
+		default:
+			return collected
+		}
+	}
+}
+
+func TestClientMetrics(t *testing.T) {
+	// prepare
+	tf := thrift.NewTFramedTransportFactoryConf(thrift.NewTTransportFactory(), nil)
+	addr := startServer(t, tf, thrift.NewTBinaryProtocolFactoryConf(nil))
+	client, samples := newVUClient(t, xk6_thrift.TClientOptions{URL: "tcp://" + addr, Transport: "framed"})
+
+	// do
+	client.Call("simpleCall", simpleCallRequest("ID"), nil)
+	client.Call("simpleCall", simpleCallRequest("FAILURE"), nil)
+
+	// verify
+	actual := collectSamples(samples)
+	if len(actual["thrift_reqs"]) != 2 || len(actual["thrift_req_duration"]) != 2 || len(actual["thrift_req_failed"]) != 2 {
+		t.Fatalf("expected 2 samples of each metric, but was %v", actual)
+	}
+	success, failure := actual["thrift_req_failed"][0], actual["thrift_req_failed"][1]
+	if success.Value != 0 || success.Tags.Map()["status"] != "ok" {
+		t.Errorf("expected successful call, but was %v %v", success.Value, success.Tags.Map())
+	}
+	if failure.Value != 1 || failure.Tags.Map()["status"] != "application" || failure.Tags.Map()["error_code"] != "INTERNAL_ERROR" {
+		t.Errorf("expected failed call, but was %v %v", failure.Value, failure.Tags.Map())
+	}
+	if method := success.Tags.Map()["method"]; method != "simpleCall" {
+		t.Errorf("expected simpleCall, but was %s", method)
+	}
+}
+
+func TestClientMetrics_dataSize(t *testing.T) {
+	// expected request is a frame of simpleCall message
+	buf := thrift.NewTMemoryBuffer()
+	prot := thrift.NewTBinaryProtocolConf(buf, nil)
+	if err := thrift.NewTStandardClient(prot, prot).Send(context.Background(), prot, 1, "simpleCall", simpleCallRequest("ID")); err != nil {
+		t.Fatal(err)
+	}
+	expected := float64(4 + buf.Len())
+
+	// prepare
+	tf := thrift.NewTFramedTransportFactoryConf(thrift.NewTTransportFactory(), nil)
+	addr, _ := startExtendedServer(t, tf, thrift.NewTBinaryProtocolFactoryConf(nil))
+	client, samples := newVUClient(t, xk6_thrift.TClientOptions{URL: "tcp://" + addr, Transport: "framed"})
+
+	// do
+	client.Call("simpleCall", simpleCallRequest("ID"), nil)
+	client.CallOneway("emit", simpleCallRequest("ID"), nil)
+
+	// verify
+	actual := collectSamples(samples)
+	if len(actual["thrift_req_size"]) != 2 || len(actual["data_sent"]) != 2 {
+		t.Fatalf("expected 2 samples of sent bytes, but was %v", actual)
+	}
+	if size := actual["thrift_req_size"][0].Value; size != expected {
+		t.Errorf("expected request size %v, but was %v", expected, size)
+	}
+	if sent := actual["data_sent"][0].Value; sent != expected {
+		t.Errorf("expected data_sent %v, but was %v", expected, sent)
+	}
+	// oneway call receives nothing
+	if len(actual["thrift_resp_size"]) != 1 || len(actual["data_received"]) != 1 {
+		t.Fatalf("expected 1 sample of received bytes, but was %v", actual)
+	}
+	if size := actual["thrift_resp_size"][0].Value; size <= 4 {
+		t.Errorf("expected response size larger than frame header, but was %v", size)
+	}
+}
+
+func TestClientMetrics_dataSizeHTTP(t *testing.T) {
+	// prepare
+	server := startHTTPServer(t, thrift.NewTBinaryProtocolFactoryConf(nil))
+	client, samples := newVUClient(t, xk6_thrift.TClientOptions{URL: server.URL})
+
+	// do
+	client.Call("simpleCall", simpleCallRequest("ID"), nil)
+
+	// verify
+	actual := collectSamples(samples)
+	if len(actual["thrift_req_size"]) != 1 || len(actual["thrift_resp_size"]) != 1 {
+		t.Fatalf("expected a sample of each size, but was %v", actual)
+	}
+	if actual["data_received"][0].Value != actual["thrift_resp_size"][0].Value {
+		t.Errorf("expected the same data_received as response size, but was %v", actual["data_received"][0].Value)
+	}
+}
+
+// phaseValues returns durations of phases of the i-th call.
+func phaseValues(actual map[string][]metrics.Sample, i int) map[string]time.Duration {
+	phases := map[string]time.Duration{}
+	for _, name := range []string{"connecting", "tls_handshaking", "sending", "waiting", "receiving", "decoding"} {
+		if samples := actual["thrift_req_"+name]; len(samples) > i {
+			phases[name] = time.Duration(samples[i].Value * float64(time.Millisecond))
+		}
+	}
+	return phases
+}
+
+func TestClientMetrics_phases(t *testing.T) {
+	certs := newCertificates(t)
+	cfg := certs.serverTLSConfig(t, false)
+	tf := thrift.NewTFramedTransportFactoryConf(thrift.NewTTransportFactory(), nil)
+	addr := startTLSServer(t, tf, thrift.NewTBinaryProtocolFactoryConf(nil), cfg)
+	server := startHTTPServerTLS(t, thrift.NewTBinaryProtocolFactoryConf(nil), cfg)
+
+	cases := map[string]xk6_thrift.TClientOptions{
+		"framed": {URL: "tcps://" + addr, Transport: "framed", TLS: xk6_thrift.TTLSOptions{CA: certs.CA}},
+		"http":   {URL: server.URL, TLS: xk6_thrift.TTLSOptions{CA: certs.CA}},
+	}
+
+	for title, opts := range cases {
+		t.Run(title, func(t *testing.T) {
+			// prepare
+			client, samples := newVUClient(t, opts)
+
+			// do
+			client.Call("simpleCall", simpleCallRequest("SLEEP:200ms"), nil)
+			client.Call("simpleCall", simpleCallRequest("ID"), nil)
+
+			// verify
+			actual := collectSamples(samples)
+			first, second := phaseValues(actual, 0), phaseValues(actual, 1)
+			if len(first) != 6 || len(second) != 6 {
+				t.Fatalf("expected all phases, but was %v %v", first, second)
+			}
+			if first["connecting"] <= 0 || first["tls_handshaking"] <= 0 {
+				t.Errorf("expected connecting and TLS handshake of new connection, but was %v", first)
+			}
+			if first["waiting"] < 200*time.Millisecond {
+				t.Errorf("expected waiting for the server, but was %v", first)
+			}
+			if second["connecting"] != 0 || second["tls_handshaking"] != 0 {
+				t.Errorf("expected no connecting with reused connection, but was %v", second)
+			}
+		})
+	}
+}
+
+func TestClientMetrics_httpStatus(t *testing.T) {
+	// prepare
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+	client, samples := newVUClient(t, xk6_thrift.TClientOptions{URL: server.URL})
+
+	// do
+	client.Call("simpleCall", simpleCallRequest("ID"), nil)
+
+	// verify
+	actual := collectSamples(samples)
+	if len(actual["thrift_reqs"]) != 1 {
+		t.Fatalf("expected 1 sample, but was %v", actual)
+	}
+	tags := actual["thrift_reqs"][0].Tags.Map()
+	if tags["http_status"] != "503" || tags["status"] != "http_status" || tags["error_code"] != "503" {
+		t.Errorf("expected tags of status 503, but was %v", tags)
+	}
+}
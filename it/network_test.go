@@ -0,0 +1,132 @@
+package it
+
+import (
+	"crypto/tls"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	xk6_thrift "github.com/lavenderses/xk6-thrift"
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/netext"
+	"go.k6.io/k6/lib/types"
+)
+
+// newDialer returns Dialer of k6 resolving hostname to 127.0.0.1, and blocking hostnames matching blocked.
+func newDialer(t *testing.T, hostname string, blocked ...string) *netext.Dialer {
+	t.Helper()
+	dialer := netext.NewDialer(net.Dialer{}, netext.NewResolver(net.LookupIP, 0, types.DNSfirst, types.DNSpreferIPv4))
+	hosts, err := types.NewHosts(map[string]types.Host{hostname: {IP: net.ParseIP("127.0.0.1")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dialer.Hosts = hosts
+	if len(blocked) > 0 {
+		trie, err := types.NewHostnameTrie(blocked)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dialer.BlockedHostnames = trie
+	}
+	return dialer
+}
+
+// withHost replaces host of url with hostname, keeping the port.
+func withHost(url, hostname string) string {
+	return strings.Replace(url, "127.0.0.1", hostname, 1)
+}
+
+func TestClientNetwork_hosts(t *testing.T) {
+	tf := thrift.NewTFramedTransportFactoryConf(thrift.NewTTransportFactory(), nil)
+	addr := startServer(t, tf, thrift.NewTBinaryProtocolFactoryConf(nil))
+	server := startHTTPServer(t, thrift.NewTBinaryProtocolFactoryConf(nil))
+
+	cases := map[string]xk6_thrift.TClientOptions{
+		"framed": {URL: withHost("tcp://"+addr, "thrift.test"), Transport: "framed"},
+		"http":   {URL: withHost(server.URL, "thrift.test")},
+	}
+
+	for title, opts := range cases {
+		t.Run(title, func(t *testing.T) {
+			// prepare
+			dialer := newDialer(t, "thrift.test")
+			client, samples := newVUClientWithState(t, opts, &lib.State{Dialer: dialer})
+
+			// do
+			actual := client.Call("simpleCall", simpleCallRequest("ID"), nil)
+
+			// verify
+			if !actual.IsSuccess() {
+				t.Fatalf("expected success with hosts of k6, but failed. %v", actual)
+			}
+			// bytes are counted by Dialer of k6 instead
+			if dialer.BytesWritten == 0 || dialer.BytesRead == 0 {
+				t.Errorf("expected bytes counted by dialer, but was %d %d", dialer.BytesWritten, dialer.BytesRead)
+			}
+			pushed := collectSamples(samples)
+			if len(pushed["data_sent"]) != 0 || len(pushed["data_received"]) != 0 {
+				t.Errorf("expected no data_sent and data_received, but was %v", pushed)
+			}
+		})
+	}
+}
+
+func TestClientNetwork_blockHostnames(t *testing.T) {
+	tf := thrift.NewTFramedTransportFactoryConf(thrift.NewTTransportFactory(), nil)
+	addr := startServer(t, tf, thrift.NewTBinaryProtocolFactoryConf(nil))
+	server := startHTTPServer(t, thrift.NewTBinaryProtocolFactoryConf(nil))
+
+	cases := map[string]xk6_thrift.TClientOptions{
+		"framed": {URL: withHost("tcp://"+addr, "thrift.test"), Transport: "framed"},
+		"http":   {URL: withHost(server.URL, "thrift.test")},
+	}
+
+	for title, opts := range cases {
+		t.Run(title, func(t *testing.T) {
+			// prepare
+			client, _ := newVUClientWithState(t, opts, &lib.State{Dialer: newDialer(t, "thrift.test", "*.test")})
+
+			// do
+			actual := client.Call("simpleCall", simpleCallRequest("ID"), nil)
+
+			// verify
+			if actual.IsSuccess() {
+				t.Fatal("expected blocked hostname, but succeeded")
+			}
+			if !strings.Contains(actual.Error(), "blocked") {
+				t.Errorf("expected blocked hostname error, but was %v", actual.Error())
+			}
+		})
+	}
+}
+
+func TestClientNetwork_insecureSkipTLSVerify(t *testing.T) {
+	certs := newCertificates(t)
+	cfg := certs.serverTLSConfig(t, false)
+	tf := thrift.NewTFramedTransportFactoryConf(thrift.NewTTransportFactory(), nil)
+	addr := startTLSServer(t, tf, thrift.NewTBinaryProtocolFactoryConf(nil), cfg)
+	server := startHTTPServerTLS(t, thrift.NewTBinaryProtocolFactoryConf(nil), cfg)
+
+	cases := map[string]xk6_thrift.TClientOptions{
+		"framed": {URL: "tcps://" + addr, Transport: "framed"},
+		"http":   {URL: server.URL},
+	}
+
+	for title, opts := range cases {
+		t.Run(title, func(t *testing.T) {
+			// prepare
+			// the client doesn't trust CA of the server
+			state := &lib.State{Dialer: newDialer(t, "thrift.test"), TLSConfig: &tls.Config{InsecureSkipVerify: true}}
+			client, _ := newVUClientWithState(t, opts, state)
+
+			// do
+			actual := client.Call("simpleCall", simpleCallRequest("ID"), nil)
+
+			// verify
+			if !actual.IsSuccess() {
+				t.Fatalf("expected success with insecureSkipTLSVerify of k6, but failed. %v", actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package it
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	xk6_thrift "github.com/lavenderses/xk6-thrift"
+)
+
+func TestClientCallOneway(t *testing.T) {
+	for _, transport := range []string{"socket", "framed"} {
+		t.Run(transport, func(t *testing.T) {
+			// prepare
+			var tf thrift.TTransportFactory = thrift.NewTTransportFactory()
+			if transport == "framed" {
+				tf = thrift.NewTFramedTransportFactoryConf(tf, nil)
+			}
+			addr, received := startExtendedServer(t, tf, thrift.NewTBinaryProtocolFactoryConf(nil))
+			client := newClient(t, xk6_thrift.TClientOptions{URL: "tcp://" + addr, Transport: transport})
+
+			// do
+			actual := client.CallOneway("emit", simpleCallRequest("EVENT"), nil)
+
+			// verify
+			if !actual.IsSuccess() {
+				t.Fatalf("expected success, but failed. %v", actual)
+			}
+			select {
+			case id := <-received:
+				if id != "EVENT" {
+					t.Errorf("expected EVENT, but was %s", id)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("oneway message is not received")
+			}
+			// no reply is left in the reused connection
+			if res := client.Call("simpleCall", simpleCallRequest("ID"), nil); !res.IsSuccess() {
+				t.Errorf("expected success after oneway call, but failed. %v", res)
+			}
+		})
+	}
+}
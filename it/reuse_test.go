@@ -0,0 +1,124 @@
+package it
+
+import (
+	"testing"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	xk6_thrift "github.com/lavenderses/xk6-thrift"
+)
+
+func TestClientReuse(t *testing.T) {
+	cases := map[string]struct {
+		reuse          string
+		reconnectEvery int
+		expected       int32
+	}{
+		"per-call":        {"per-call", 0, 4},
+		"per-vu":          {"per-vu", 0, 1},
+		"reconnect every": {"per-vu", 3, 2},
+	}
+
+	for title, c := range cases {
+		t.Run("framed/"+title, func(t *testing.T) {
+			// prepare
+			tf := thrift.NewTFramedTransportFactoryConf(thrift.NewTTransportFactory(), nil)
+			addr, accepted := startCountingServer(t, tf, thrift.NewTBinaryProtocolFactoryConf(nil))
+			client := newClient(t, xk6_thrift.TClientOptions{
+				URL:            "tcp://" + addr,
+				Transport:      "framed",
+				Reuse:          c.reuse,
+				ReconnectEvery: c.reconnectEvery,
+			})
+
+			// do
+			for i := 0; i < 4; i++ {
+				if res := client.Call("simpleCall", simpleCallRequest("ID"), nil); !res.IsSuccess() {
+					t.Fatalf("expected success, but failed. %v", res)
+				}
+			}
+
+			// verify
+			if actual := accepted.Load(); actual != c.expected {
+				t.Errorf("expected %d connections, but was %d", c.expected, actual)
+			}
+		})
+
+		t.Run("http/"+title, func(t *testing.T) {
+			// prepare
+			server := startHTTPServer(t, thrift.NewTBinaryProtocolFactoryConf(nil))
+			client := newClient(t, xk6_thrift.TClientOptions{
+				URL:            server.URL,
+				Reuse:          c.reuse,
+				ReconnectEvery: c.reconnectEvery,
+			})
+
+			// do
+			for i := 0; i < 4; i++ {
+				if res := client.Call("simpleCall", simpleCallRequest("ID"), nil); !res.IsSuccess() {
+					t.Fatalf("expected success, but failed. %v", res)
+				}
+			}
+
+			// verify
+			if actual := server.Connections.Load(); actual != c.expected {
+				t.Errorf("expected %d connections, but was %d", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestClientReuse_httpAfterError(t *testing.T) {
+	// prepare
+	server := startHTTPServer(t, thrift.NewTBinaryProtocolFactoryConf(nil))
+	client := newClient(t, xk6_thrift.TClientOptions{URL: server.URL})
+
+	// do
+	first := client.Call("simpleCall", simpleCallRequest("ID"), nil)
+	second := client.Call("simpleCall", simpleCallRequest("ID"), &xk6_thrift.TCallOptions{
+		Headers: map[string]string{"X-Status": "503"},
+	})
+	third := client.Call("simpleCall", simpleCallRequest("ID"), nil)
+
+	// verify
+	if !first.IsSuccess() || !third.IsSuccess() {
+		t.Fatalf("expected success, but failed. %v %v", first, third)
+	}
+	if second.IsSuccess() {
+		t.Errorf("expected failure, but succeeded.")
+	}
+	// TCP connection pooled by http.Client is kept after the failed call
+	if actual := server.Connections.Load(); actual != 1 {
+		t.Errorf("expected 1 connection, but was %d", actual)
+	}
+}
+
+func TestClientReuse_afterApplicationError(t *testing.T) {
+	for _, protocol := range []string{"binary", "header"} {
+		t.Run(protocol, func(t *testing.T) {
+			// prepare
+			var addr string
+			if protocol == "header" {
+				addr = startHeaderServer(t)
+			} else {
+				addr = startServer(t, thrift.NewTTransportFactory(), thrift.NewTBinaryProtocolFactoryConf(nil))
+			}
+			client := newClient(t, xk6_thrift.TClientOptions{
+				URL:       "tcp://" + addr,
+				Protocol:  protocol,
+				Transport: "socket",
+			})
+
+			// do
+			first := client.Call("simpleCall", simpleCallRequest("FAILURE"), nil)
+			second := client.Call("simpleCall", simpleCallRequest("ID"), nil)
+
+			// verify
+			if first.IsSuccess() {
+				t.Errorf("expected failure, but succeeded.")
+			}
+			if !second.IsSuccess() {
+				t.Errorf("expected success on reused connection, but failed. %v", second)
+			}
+		})
+	}
+}
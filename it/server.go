@@ -0,0 +1,475 @@
+package it
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/lavenderses/xk6-thrift/pkg/gen-go/idl"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// testService is a Go implementation of TestService in idl/idl.thrift,
+// which behaves the same as the server in server/ directory.
+type testService struct{}
+
+var _ idl.TestService = (*testService)(nil)
+
+func (*testService) SimpleCall(cxt context.Context, id string) (string, error) {
+	// echo back THeader headers
+	if helper, ok := thrift.GetResponseHelper(cxt); ok {
+		for _, k := range thrift.GetReadHeaderList(cxt) {
+			v, _ := thrift.GetHeader(cxt, k)
+			helper.SetHeader(k, v)
+		}
+	}
+
+	// Sleep if specified such as "SLEEP:1s", until the client goes away
+	if d, ok := strings.CutPrefix(id, "SLEEP:"); ok {
+		duration, err := time.ParseDuration(d)
+		if err != nil {
+			return "", err
+		}
+		select {
+		case <-time.After(duration):
+		case <-cxt.Done():
+		}
+	}
+
+	// Make failure if specified
+	if id == "FAILURE" {
+		return "", fmt.Errorf("Make failure: %s", id)
+	}
+	return "Success: " + id, nil
+}
+
+func (*testService) BoolCall(_ context.Context, tf bool) (bool, error) {
+	return tf, nil
+}
+
+func (*testService) MessageCall(_ context.Context, message *idl.Message) (*idl.Message, error) {
+	return &idl.Message{Content: "content: " + message.Content, Tags: message.Tags, Nested: message.Nested}, nil
+}
+
+func (*testService) MapCall(_ context.Context, maps map[string]bool) (map[string]bool, error) {
+	res := make(map[string]bool)
+	for k, v := range maps {
+		res["NEW: "+k] = v
+	}
+	return res, nil
+}
+
+func (*testService) StringCall(_ context.Context, strs []string) ([]string, error) {
+	var res []string
+	for _, s := range strs {
+		res = append(res, s+":"+s)
+	}
+	return res, nil
+}
+
+func (*testService) StringsCall(_ context.Context, strs []*idl.Message) ([]*idl.Message, error) {
+	var res []*idl.Message
+	for _, m := range strs {
+		res = append(res, &idl.Message{Content: "content: " + m.Content, Tags: m.Tags, Nested: m.Nested})
+	}
+	return res, nil
+}
+
+func (*testService) EnumCall(_ context.Context, _ idl.Feature) ([]idl.Feature, error) {
+	return []idl.Feature{idl.Feature_ONE, idl.Feature_TWO, idl.Feature_THREE}, nil
+}
+
+// emitFunction is a oneway method `oneway void emit(1: string id)`, which is not defined in idl/idl.thrift.
+// Received IDs are sent to the channel.
+type emitFunction struct {
+	received chan<- string
+}
+
+func (f *emitFunction) Process(cxt context.Context, _ int32, in, _ thrift.TProtocol) (bool, thrift.TException) {
+	// arguments are the same as simpleCall
+	args := idl.NewTestServiceSimpleCallArgs()
+	if err := readArgs(cxt, in, args); err != nil {
+		return false, err
+	}
+	f.received <- args.ID
+	return true, nil
+}
+
+// startServer starts TestService server on a random local TCP port, and returns its address.
+// Server is stopped when the test finishes.
+func startServer(t *testing.T, tf thrift.TTransportFactory, pf thrift.TProtocolFactory) string {
+	sock, err := thrift.NewTServerSocket("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error creating server socket. %v", err)
+	}
+	return serve(t, sock, thrift.NewTSimpleServer4(idl.NewTestServiceProcessor(&testService{}), sock, tf, pf))
+}
+
+// barrierService is testService whose SimpleCall waits until the given number of calls are in flight,
+// or 5 seconds pass. It records the maximum number of calls in flight at the same time.
+type barrierService struct {
+	testService
+	parallel int32
+	arrived  chan struct{}
+	once     sync.Once
+
+	inFlight    atomic.Int32
+	maxInFlight atomic.Int32
+}
+
+func (s *barrierService) SimpleCall(cxt context.Context, id string) (string, error) {
+	n := s.inFlight.Add(1)
+	defer s.inFlight.Add(-1)
+	for {
+		m := s.maxInFlight.Load()
+		if n <= m || s.maxInFlight.CompareAndSwap(m, n) {
+			break
+		}
+	}
+	if n >= s.parallel {
+		s.once.Do(func() { close(s.arrived) })
+	}
+
+	select {
+	case <-s.arrived:
+	case <-time.After(5 * time.Second):
+	case <-cxt.Done():
+	}
+	return s.testService.SimpleCall(cxt, id)
+}
+
+// startBarrierServer is the same as startServer, but SimpleCall waits until parallel calls are in flight.
+// It returns the service to see the maximum number of calls in flight.
+func startBarrierServer(t *testing.T, tf thrift.TTransportFactory, pf thrift.TProtocolFactory, parallel int32) (string, *barrierService) {
+	sock, err := thrift.NewTServerSocket("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error creating server socket. %v", err)
+	}
+	service := &barrierService{parallel: parallel, arrived: make(chan struct{})}
+	return serve(t, sock, thrift.NewTSimpleServer4(idl.NewTestServiceProcessor(service), sock, tf, pf)), service
+}
+
+// throwFunction is a method `string throw(1: string id) throws (1: NotFound notFound, 2: Invalid invalid)`,
+// which is not defined in idl/idl.thrift. It always throws NotFound, which is encoded as Nested in idl/idl.thrift.
+type throwFunction struct{}
+
+func (*throwFunction) Process(cxt context.Context, seqID int32, in, out thrift.TProtocol) (bool, thrift.TException) {
+	args := idl.NewTestServiceSimpleCallArgs()
+	if err := readArgs(cxt, in, args); err != nil {
+		return false, err
+	}
+	if err := writeResult(cxt, out, "throw", seqID, &idl.Nested{Inner: "not found: " + args.ID}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// pingFunction is a void method `void ping(1: string id) throws (1: NotFound notFound)`, which is not defined
+// in idl/idl.thrift. It throws NotFound only when id is "NOT_FOUND".
+type pingFunction struct{}
+
+func (*pingFunction) Process(cxt context.Context, seqID int32, in, out thrift.TProtocol) (bool, thrift.TException) {
+	args := idl.NewTestServiceSimpleCallArgs()
+	if err := readArgs(cxt, in, args); err != nil {
+		return false, err
+	}
+	var notFound *idl.Nested
+	if args.ID == "NOT_FOUND" {
+		notFound = &idl.Nested{Inner: "not found: " + args.ID}
+	}
+	if err := writeResult(cxt, out, "ping", seqID, notFound); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func readArgs(cxt context.Context, in thrift.TProtocol, args thrift.TStruct) thrift.TException {
+	if err := args.Read(cxt, in); err != nil {
+		return thrift.WrapTException(err)
+	}
+	if err := in.ReadMessageEnd(cxt); err != nil {
+		return thrift.WrapTException(err)
+	}
+	return nil
+}
+
+// writeResult writes REPLY message of result struct without return value, whose field 1 is notFound
+// when it is not nil.
+func writeResult(cxt context.Context, out thrift.TProtocol, method string, seqID int32, notFound *idl.Nested) thrift.TException {
+	err := func() error {
+		if err := out.WriteMessageBegin(cxt, method, thrift.REPLY, seqID); err != nil {
+			return err
+		}
+		if err := out.WriteStructBegin(cxt, method+"_result"); err != nil {
+			return err
+		}
+		if notFound != nil {
+			if err := out.WriteFieldBegin(cxt, "notFound", thrift.STRUCT, 1); err != nil {
+				return err
+			}
+			if err := notFound.Write(cxt, out); err != nil {
+				return err
+			}
+			if err := out.WriteFieldEnd(cxt); err != nil {
+				return err
+			}
+		}
+		if err := out.WriteFieldStop(cxt); err != nil {
+			return err
+		}
+		if err := out.WriteStructEnd(cxt); err != nil {
+			return err
+		}
+		if err := out.WriteMessageEnd(cxt); err != nil {
+			return err
+		}
+		return out.Flush(cxt)
+	}()
+	if err != nil {
+		return thrift.WrapTException(err)
+	}
+	return nil
+}
+
+// startExtendedServer is the same as startServer, but also serves oneway `emit` method, `throw` method
+// and void `ping` method. It returns a channel receiving IDs given to `emit`.
+func startExtendedServer(t *testing.T, tf thrift.TTransportFactory, pf thrift.TProtocolFactory) (string, <-chan string) {
+	sock, err := thrift.NewTServerSocket("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error creating server socket. %v", err)
+	}
+
+	received := make(chan string, 10)
+	processor := idl.NewTestServiceProcessor(&testService{})
+	processor.AddToProcessorMap("emit", &emitFunction{received: received})
+	processor.AddToProcessorMap("throw", &throwFunction{})
+	processor.AddToProcessorMap("ping", &pingFunction{})
+	return serve(t, sock, thrift.NewTSimpleServer4(processor, sock, tf, pf)), received
+}
+
+// startMultiplexedServer is the same as startServer, but TestService is registered to TMultiplexedProcessor
+// with each name of services.
+func startMultiplexedServer(t *testing.T, tf thrift.TTransportFactory, pf thrift.TProtocolFactory, services ...string) string {
+	sock, err := thrift.NewTServerSocket("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error creating server socket. %v", err)
+	}
+
+	processor := thrift.NewTMultiplexedProcessor()
+	for _, service := range services {
+		processor.RegisterProcessor(service, idl.NewTestServiceProcessor(&testService{}))
+	}
+	return serve(t, sock, thrift.NewTSimpleServer4(processor, sock, tf, pf))
+}
+
+// countingServerSocket is TServerSocket counting accepted connections.
+type countingServerSocket struct {
+	*thrift.TServerSocket
+	accepted atomic.Int32
+}
+
+func (s *countingServerSocket) Accept() (thrift.TTransport, error) {
+	trans, err := s.TServerSocket.Accept()
+	if err == nil {
+		s.accepted.Add(1)
+	}
+	return trans, err
+}
+
+// startCountingServer is the same as startServer, but also returns the number of accepted connections.
+func startCountingServer(t *testing.T, tf thrift.TTransportFactory, pf thrift.TProtocolFactory) (string, *atomic.Int32) {
+	inner, err := thrift.NewTServerSocket("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error creating server socket. %v", err)
+	}
+	sock := &countingServerSocket{TServerSocket: inner}
+	return serve(t, sock, thrift.NewTSimpleServer4(idl.NewTestServiceProcessor(&testService{}), sock, tf, pf)), &sock.accepted
+}
+
+// tlsServerSocket is TServerSocket accepting TLS connections. This is used instead of TSSLServerSocket, whose
+// Interrupt races with Accept and doesn't stop the blocked Accept.
+type tlsServerSocket struct {
+	*thrift.TServerSocket
+	cfg *tls.Config
+}
+
+func (s *tlsServerSocket) Accept() (thrift.TTransport, error) {
+	trans, err := s.TServerSocket.Accept()
+	if err != nil {
+		return nil, err
+	}
+	conn := tls.Server(trans.(*thrift.TSocket).Conn(), s.cfg)
+	return thrift.NewTSSLSocketFromConnTimeout(conn, s.cfg, 0), nil
+}
+
+// startTLSServer is the same as startServer, but accepts only TLS connections.
+func startTLSServer(t *testing.T, tf thrift.TTransportFactory, pf thrift.TProtocolFactory, cfg *tls.Config) string {
+	inner, err := thrift.NewTServerSocket("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error creating server socket. %v", err)
+	}
+	sock := &tlsServerSocket{TServerSocket: inner, cfg: cfg}
+	return serve(t, sock, thrift.NewTSimpleServer4(idl.NewTestServiceProcessor(&testService{}), sock, tf, pf))
+}
+
+type listeningServerTransport interface {
+	thrift.TServerTransport
+	Addr() net.Addr
+}
+
+// startHeaderServer is the same as startServer, but uses THeaderTransport and THeaderProtocol.
+// When services are given, TestService is registered to TMultiplexedProcessor with each name of them.
+func startHeaderServer(t *testing.T, services ...string) string {
+	sock, err := thrift.NewTServerSocket("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error creating server socket. %v", err)
+	}
+
+	var processor thrift.TProcessor = idl.NewTestServiceProcessor(&testService{})
+	if len(services) > 0 {
+		multiplexed := thrift.NewTMultiplexedProcessor()
+		for _, service := range services {
+			multiplexed.RegisterProcessor(service, idl.NewTestServiceProcessor(&testService{}))
+		}
+		processor = multiplexed
+	}
+
+	server := thrift.NewTSimpleServer4(
+		processor,
+		sock,
+		thrift.NewTHeaderTransportFactoryConf(nil, nil),
+		thrift.NewTHeaderProtocolFactoryConf(nil),
+	)
+	return serve(t, sock, server)
+}
+
+func serve(t *testing.T, sock listeningServerTransport, server *thrift.TSimpleServer) string {
+	// listen before serving to know the port
+	if err := sock.Listen(); err != nil {
+		t.Fatalf("error listening. %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = server.Serve()
+	}()
+	t.Cleanup(func() {
+		// Stop interrupts the socket, which closes the listener and makes Serve return
+		_ = server.Stop()
+		<-done
+	})
+
+	return sock.Addr().String()
+}
+
+// httpServer is TestService server using HTTP as transport.
+type httpServer struct {
+	URL string
+	// Connections is the number of accepted TCP connections.
+	Connections atomic.Int32
+
+	mu           sync.Mutex
+	contentTypes []string
+	headers      []http.Header
+	protos       []string
+}
+
+// ContentTypes returns Content-Type headers of all received requests.
+func (s *httpServer) ContentTypes() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.contentTypes...)
+}
+
+// Protos returns HTTP protocol versions such as "HTTP/2.0" of all received requests.
+func (s *httpServer) Protos() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.protos...)
+}
+
+// Headers returns headers of all received requests.
+func (s *httpServer) Headers() []http.Header {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]http.Header{}, s.headers...)
+}
+
+// startHTTPServer starts TestService server with HTTP transport on a random local port.
+// Server is stopped when the test finishes.
+func startHTTPServer(t *testing.T, pf thrift.TProtocolFactory) *httpServer {
+	return startHTTPServerTLS(t, pf, nil)
+}
+
+// startHTTPServerTLS is the same as startHTTPServer, but serves HTTPS when cfg is not nil.
+func startHTTPServerTLS(t *testing.T, pf thrift.TProtocolFactory, cfg *tls.Config) *httpServer {
+	s, server := newHTTPServer(pf, false)
+	if cfg != nil {
+		server.TLS = cfg
+		server.StartTLS()
+	} else {
+		server.Start()
+	}
+	t.Cleanup(server.Close)
+
+	s.URL = server.URL + "/thrift"
+	return s
+}
+
+// startH2CServer is the same as startHTTPServer, but serves HTTP/2 over cleartext as well.
+func startH2CServer(t *testing.T, pf thrift.TProtocolFactory) *httpServer {
+	s, server := newHTTPServer(pf, true)
+	server.Start()
+	t.Cleanup(server.Close)
+
+	s.URL = server.URL + "/thrift"
+	return s
+}
+
+// newHTTPServer returns a server of TestService over HTTP, which is not started yet.
+func newHTTPServer(pf thrift.TProtocolFactory, h2cEnabled bool) (*httpServer, *httptest.Server) {
+	processor := idl.NewTestServiceProcessor(&testService{})
+	handler := thrift.NewThriftHandlerFunc(processor, pf, pf)
+
+	s := &httpServer{}
+	var h http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.contentTypes = append(s.contentTypes, r.Header.Get("Content-Type"))
+		s.headers = append(s.headers, r.Header.Clone())
+		s.protos = append(s.protos, r.Proto)
+		s.mu.Unlock()
+		// X-Request-Id is echoed back as a response header
+		if id := r.Header.Get("X-Request-Id"); id != "" {
+			w.Header().Set("X-Request-Id", id)
+		}
+		// X-Status makes the response fail with the status
+		if status := r.Header.Get("X-Status"); status != "" {
+			code, _ := strconv.Atoi(status)
+			w.WriteHeader(code)
+			return
+		}
+		handler(w, r)
+	})
+	if h2cEnabled {
+		h = h2c.NewHandler(h, &http2.Server{})
+	}
+	server := httptest.NewUnstartedServer(h)
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			s.Connections.Add(1)
+		}
+	}
+	return s, server
+}
@@ -364,7 +364,11 @@ func TestEnumCall(t *testing.T) {
 	expectTValue := xk6_thrift.NewTList(&expectValue, thrift.I32)
 	expect := xk6_thrift.NewTResponse()
 	expect.Add(0, expectTValue)
-	actual := xk6_thrift.NewTResponse()
+	// i32 is decoded as enum only with the hint
+	actual := xk6_thrift.NewTResponseWithHint(&xk6_thrift.TTypeHint{
+		Type: "list",
+		Elem: &xk6_thrift.TTypeHint{Type: "enum"},
+	})
 
 	// do & verify
 	if _, err = (*client).Call(cxt, method, arg, actual); err != nil {
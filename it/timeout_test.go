@@ -0,0 +1,136 @@
+package it
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	xk6_thrift "github.com/lavenderses/xk6-thrift"
+	"go.k6.io/k6/js/modulestest"
+)
+
+func TestClientTimeout(t *testing.T) {
+	cases := map[string]struct {
+		opts     xk6_thrift.TClientOptions
+		callOpts *xk6_thrift.TCallOptions
+	}{
+		"timeout":          {xk6_thrift.TClientOptions{Timeout: "100ms"}, nil},
+		"per-call timeout": {xk6_thrift.TClientOptions{Timeout: "10s"}, &xk6_thrift.TCallOptions{Timeout: "100ms"}},
+		"read timeout":     {xk6_thrift.TClientOptions{ReadTimeout: "100ms"}, nil},
+	}
+
+	for title, c := range cases {
+		t.Run("framed/"+title, func(t *testing.T) {
+			// prepare
+			tf := thrift.NewTFramedTransportFactoryConf(thrift.NewTTransportFactory(), nil)
+			addr := startServer(t, tf, thrift.NewTBinaryProtocolFactoryConf(nil))
+			opts := c.opts
+			opts.URL = "tcp://" + addr
+			opts.Transport = "framed"
+			client := newClient(t, opts)
+
+			// do
+			start := time.Now()
+			actual := client.Call("simpleCall", simpleCallRequest("SLEEP:5s"), c.callOpts)
+			elapsed := time.Since(start)
+
+			// verify
+			verifyTimeout(t, actual, elapsed)
+			// the timed out connection is not reused
+			if res := client.Call("simpleCall", simpleCallRequest("ID"), nil); !res.IsSuccess() {
+				t.Errorf("expected success after timeout, but failed. %v", res)
+			}
+		})
+
+		t.Run("http/"+title, func(t *testing.T) {
+			// prepare
+			server := startHTTPServer(t, thrift.NewTBinaryProtocolFactoryConf(nil))
+			opts := c.opts
+			opts.URL = server.URL
+			client := newClient(t, opts)
+
+			// do
+			start := time.Now()
+			actual := client.Call("simpleCall", simpleCallRequest("SLEEP:5s"), c.callOpts)
+			elapsed := time.Since(start)
+
+			// verify
+			verifyTimeout(t, actual, elapsed)
+			if res := client.Call("simpleCall", simpleCallRequest("ID"), nil); !res.IsSuccess() {
+				t.Errorf("expected success after timeout, but failed. %v", res)
+			}
+		})
+	}
+}
+
+func TestClientTimeout_notExceeded(t *testing.T) {
+	// prepare
+	tf := thrift.NewTFramedTransportFactoryConf(thrift.NewTTransportFactory(), nil)
+	addr := startServer(t, tf, thrift.NewTBinaryProtocolFactoryConf(nil))
+	client := newClient(t, xk6_thrift.TClientOptions{
+		URL:            "tcp://" + addr,
+		Transport:      "framed",
+		Timeout:        "5s",
+		ConnectTimeout: "1s",
+		ReadTimeout:    "1s",
+		WriteTimeout:   "1s",
+	})
+
+	// do
+	actual := client.Call("simpleCall", simpleCallRequest("SLEEP:100ms"), nil)
+
+	// verify
+	if !actual.IsSuccess() {
+		t.Fatalf("expected success, but failed. %v", actual)
+	}
+	if actual.ErrorKind() != "" {
+		t.Errorf("expected no error kind, but was %q", actual.ErrorKind())
+	}
+}
+
+func TestClientTimeout_vuContextCanceled(t *testing.T) {
+	// prepare
+	rt := modulestest.NewRuntime(t)
+	cxt, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	rt.VU.CtxField = cxt
+	tf := thrift.NewTFramedTransportFactoryConf(thrift.NewTTransportFactory(), nil)
+	addr := startServer(t, tf, thrift.NewTBinaryProtocolFactoryConf(nil))
+	module := new(xk6_thrift.TRootModule).NewModuleInstance(rt.VU).(*xk6_thrift.TModule)
+	client, err := module.NewClient(xk6_thrift.TClientOptions{URL: "tcp://" + addr, Transport: "framed"})
+	if err != nil {
+		t.Fatalf("error creating client. %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	// do
+	time.AfterFunc(100*time.Millisecond, cancel)
+	start := time.Now()
+	actual := client.Call("simpleCall", simpleCallRequest("SLEEP:5s"), nil)
+	elapsed := time.Since(start)
+
+	// verify
+	if actual.IsSuccess() {
+		t.Fatalf("expected failure, but succeeded. %v", actual)
+	}
+	if actual.ErrorKind() != "canceled" {
+		t.Errorf("expected canceled, but was %q", actual.ErrorKind())
+	}
+	if elapsed > 2*time.Second {
+		t.Errorf("expected to be canceled soon, but took %v", elapsed)
+	}
+}
+
+func verifyTimeout(t *testing.T, actual *xk6_thrift.TCallResult, elapsed time.Duration) {
+	t.Helper()
+	if actual.IsSuccess() {
+		t.Fatalf("expected timeout, but succeeded. %v", actual)
+	}
+	if actual.ErrorKind() != "timeout" || actual.ErrorCode() != "TIMED_OUT" {
+		t.Errorf("expected timeout TIMED_OUT, but was %q %q", actual.ErrorKind(), actual.ErrorCode())
+	}
+	if elapsed > 2*time.Second {
+		t.Errorf("expected to time out soon, but took %v", elapsed)
+	}
+}
@@ -0,0 +1,93 @@
+package it
+
+import (
+	"testing"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	xk6_thrift "github.com/lavenderses/xk6-thrift"
+)
+
+func TestClientTLS(t *testing.T) {
+	certs := newCertificates(t)
+	tf := thrift.NewTFramedTransportFactoryConf(thrift.NewTTransportFactory(), nil)
+	pf := thrift.NewTBinaryProtocolFactoryConf(nil)
+	cases := map[string]struct {
+		mtls    bool
+		tls     xk6_thrift.TTLSOptions
+		success bool
+	}{
+		"ca": {
+			tls:     xk6_thrift.TTLSOptions{CA: certs.CA},
+			success: true,
+		},
+		"server name": {
+			tls:     xk6_thrift.TTLSOptions{CA: certs.CA, ServerName: "localhost"},
+			success: true,
+		},
+		"wrong server name": {
+			tls:     xk6_thrift.TTLSOptions{CA: certs.CA, ServerName: "example.com"},
+			success: false,
+		},
+		"unknown ca": {
+			tls:     xk6_thrift.TTLSOptions{},
+			success: false,
+		},
+		"insecure skip verify": {
+			tls:     xk6_thrift.TTLSOptions{InsecureSkipVerify: true},
+			success: true,
+		},
+		"mtls": {
+			mtls:    true,
+			tls:     xk6_thrift.TTLSOptions{CA: certs.CA, Cert: certs.ClientCert, Key: certs.ClientKey},
+			success: true,
+		},
+		"mtls without client cert": {
+			mtls:    true,
+			tls:     xk6_thrift.TTLSOptions{CA: certs.CA},
+			success: false,
+		},
+		"tls1.3": {
+			tls:     xk6_thrift.TTLSOptions{CA: certs.CA, MinVersion: "tls1.3"},
+			success: true,
+		},
+	}
+
+	for title, c := range cases {
+		t.Run("socket/"+title, func(t *testing.T) {
+			// prepare
+			addr := startTLSServer(t, tf, pf, certs.serverTLSConfig(t, c.mtls))
+			client := newClient(t, xk6_thrift.TClientOptions{
+				URL:       "tcps://" + addr,
+				Transport: "framed",
+				Timeout:   "5s",
+				TLS:       c.tls,
+			})
+
+			// do
+			res := client.Call("simpleCall", simpleCallRequest("ID"), nil)
+
+			// verify
+			if res.IsSuccess() != c.success {
+				t.Errorf("expected success=%v, but was %v", c.success, res)
+			}
+		})
+
+		t.Run("http/"+title, func(t *testing.T) {
+			// prepare
+			server := startHTTPServerTLS(t, pf, certs.serverTLSConfig(t, c.mtls))
+			client := newClient(t, xk6_thrift.TClientOptions{
+				URL:     server.URL,
+				Timeout: "5s",
+				TLS:     c.tls,
+			})
+
+			// do
+			res := client.Call("simpleCall", simpleCallRequest("ID"), nil)
+
+			// verify
+			if res.IsSuccess() != c.success {
+				t.Errorf("expected success=%v, but was %v", c.success, res)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package it
+
+import (
+	"testing"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	xk6_thrift "github.com/lavenderses/xk6-thrift"
+)
+
+func TestClientVoid(t *testing.T) {
+	cases := map[string]struct {
+		voidMethods []string
+		callOpts    *xk6_thrift.TCallOptions
+		success     bool
+	}{
+		"void methods": {[]string{"ping"}, nil, true},
+		"void call":    {nil, &xk6_thrift.TCallOptions{Void: true}, true},
+		"not void":     {nil, nil, false},
+	}
+
+	for title, c := range cases {
+		t.Run(title, func(t *testing.T) {
+			// prepare
+			tf := thrift.NewTFramedTransportFactoryConf(thrift.NewTTransportFactory(), nil)
+			addr, _ := startExtendedServer(t, tf, thrift.NewTBinaryProtocolFactoryConf(nil))
+			client := newClient(t, xk6_thrift.TClientOptions{
+				URL:         "tcp://" + addr,
+				Transport:   "framed",
+				VoidMethods: c.voidMethods,
+			})
+
+			// do
+			actual := client.Call("ping", simpleCallRequest("ID"), c.callOpts)
+
+			// verify
+			if actual.IsSuccess() != c.success {
+				t.Errorf("expected success %v, but was %v", c.success, actual)
+			}
+			if actual.Body() != nil {
+				t.Errorf("expected no body, but was %v", actual.Body())
+			}
+		})
+	}
+}
+
+func TestClientVoid_exception(t *testing.T) {
+	// prepare
+	tf := thrift.NewTFramedTransportFactoryConf(thrift.NewTTransportFactory(), nil)
+	addr, _ := startExtendedServer(t, tf, thrift.NewTBinaryProtocolFactoryConf(nil))
+	client := newClient(t, xk6_thrift.TClientOptions{URL: "tcp://" + addr, Transport: "framed", VoidMethods: []string{"ping"}})
+
+	// do
+	actual := client.Call("ping", simpleCallRequest("NOT_FOUND"), nil)
+
+	// verify
+	if actual.IsSuccess() {
+		t.Fatalf("expected exception, but succeeded")
+	}
+	if exception := actual.Exception(); exception == nil || exception.ID != 1 {
+		t.Errorf("expected declared exception 1, but was %+v", exception)
+	}
+}
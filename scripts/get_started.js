@@ -7,13 +7,17 @@ export const options = {
   iterations: 1,
 }
 
+const client = thrift.newClient({
+  url: "http://127.0.0.1:8080/thrift",
+});
+
 export default function() {
   const method = "simpleCall";
   const values = {};
   values[1] = ttypes.newTString("ID");
   const req = ttypes.newTRequest(values);
 
-  const res = thrift.call(method, req);
+  const res = client.call(method, req);
   check(res, {
     "success Thrift call": (r) => r.isSuccess(),
   });
@@ -24,7 +28,7 @@ export default function() {
   failValue[1] = ttypes.newTString("FAILURE");
   const failReq = ttypes.newTRequest(failValue);
 
-  const failRes = thrift.call(method, failReq);
+  const failRes = client.call(method, failReq);
   check(failRes, {
     "failure Thrift call": (r) => !r.isSuccess(),
   });
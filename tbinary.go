@@ -0,0 +1,86 @@
+package thrift
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+// TBinary is Thrift `binary`, which is the same as `string` on the wire but holds arbitrary bytes.
+// Bytes are held as string, so that it can be a key of [TMap].
+type TBinary struct {
+	value string
+}
+
+func NewTBinary(v []byte) TBinary {
+	return TBinary{value: string(v)}
+}
+
+func (p TBinary) Equals(other *TValue) bool {
+	o, ok := (*other).(TBinary)
+	if !ok {
+		return false
+	}
+	return p.value == o.value
+}
+
+// See [Thrift IDL protocol spec]
+//
+//	<field> ::= <field-begin> <field-data> <field-end>
+//	<field-data> ::= BINARY
+//
+// [Thrift IDL protocol spec]: https://github.com/apache/thrift/blob/eec0b584e657e4250e22f3fd492858d632e2aa7b/doc/specs/thrift-protocol-spec.md
+func (p TBinary) WriteFieldData(cxt context.Context, oprot thrift.TProtocol) (err error) {
+	if err = oprot.WriteBinary(cxt, []byte(p.value)); err != nil {
+		err = thrift.PrependError(fmt.Sprintf("%T.id (1) field write error: ", p), err)
+		return
+	}
+	return
+}
+
+func (p TBinary) TType() thrift.TType {
+	return thrift.STRING
+}
+
+// Export returns a copy of the bytes, which is ArrayBuffer in body of [TCallResult].
+func (p TBinary) Export() any {
+	return []byte(p.value)
+}
+
+// Hex returns the bytes as a hex string. This is `value.hex()` in JavaScript.
+func (p TBinary) Hex() string {
+	return hex.EncodeToString([]byte(p.value))
+}
+
+// Base64 returns the bytes as a standard base64 string. This is `value.base64()` in JavaScript.
+func (p TBinary) Base64() string {
+	return base64.StdEncoding.EncodeToString([]byte(p.value))
+}
+
+func ReadBinary(cxt context.Context, iprot thrift.TProtocol) (TValue, error) {
+	v, err := iprot.ReadBinary(cxt)
+	if err != nil {
+		return nil, thrift.PrependError("error while reading binary: ", err)
+	}
+
+	res := NewTBinary(v)
+	return res, nil
+}
+
+// ReadStringOrBinary reads STRING field data, which is [TString] when it is valid UTF-8, or [TBinary] otherwise.
+// string and binary can't be told on the wire. Binary of JSON protocols is base64 string, so it is read as [TString].
+func ReadStringOrBinary(cxt context.Context, iprot thrift.TProtocol) (TValue, error) {
+	v, err := iprot.ReadString(cxt)
+	if err != nil {
+		return nil, thrift.PrependError("error while reading string field", err)
+	}
+
+	if !utf8.ValidString(v) {
+		return TBinary{value: v}, nil
+	}
+	return NewTstring(v), nil
+}
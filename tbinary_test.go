@@ -0,0 +1,128 @@
+package thrift
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+func TestEquals_TBinary_equals(t *testing.T) {
+	// prepare
+	a := NewTBinary([]byte{0xca, 0xfe})
+	var b TValue = NewTBinary([]byte{0xca, 0xfe})
+	expected := true
+
+	// do
+	actual := a.Equals(&b)
+
+	// verify
+	assert(t, "", actual, expected)
+}
+
+func TestEquals_TBinary_not_equals(t *testing.T) {
+	// prepare
+	a := NewTBinary([]byte{0xca, 0xfe})
+	var b TValue = NewTBinary([]byte{0xca})
+	expected := false
+
+	// do
+	actual := a.Equals(&b)
+
+	// verify
+	assert(t, "", actual, expected)
+}
+
+func TestEquals_TBinary_other_type(t *testing.T) {
+	// prepare
+	a := NewTBinary([]byte("foo"))
+	var b TValue = NewTstring("foo")
+	expected := false
+
+	// do
+	actual := a.Equals(&b)
+
+	// verify
+	assert(t, "", actual, expected)
+}
+
+func TestNewTBinary_copy(t *testing.T) {
+	// prepare
+	v := []byte{0xca, 0xfe}
+
+	// do
+	actual := NewTBinary(v)
+	v[0] = 0
+
+	// verify
+	assert(t, "", actual.Hex(), "cafe")
+	assert(t, "base64", actual.Base64(), "yv4=")
+}
+
+func TestWriteFieldData_TBinary(t *testing.T) {
+	// prepare
+	oprot := setupProtocol(t)
+	cxt := context.Background()
+	value := NewTBinary([]byte{0xca, 0xfe})
+
+	// do
+	err := value.WriteFieldData(cxt, oprot)
+	checkError(t, err)
+
+	// verify
+	oprot.Flush(cxt)
+	actual, err := oprot.ReadBinary(cxt)
+	checkError(t, err)
+	assert(t, "", string(actual), "\xca\xfe")
+}
+
+func TestReadBinary(t *testing.T) {
+	// prepare
+	iprot := setupProtocol(t)
+	cxt := context.Background()
+	checkError(
+		t,
+		iprot.WriteBinary(cxt, []byte{0xca, 0xfe}),
+	)
+	checkError(
+		t,
+		iprot.Flush(cxt),
+	)
+
+	// do
+	actual, err := ReadBinary(cxt, iprot)
+	checkError(t, err)
+
+	// verify
+	a, ok := actual.(TBinary)
+	assertTrue(t, "cast to TBinary", ok)
+	assert(t, "result", a.Hex(), "cafe")
+}
+
+func TestReadStringOrBinary(t *testing.T) {
+	cases := map[string]struct {
+		value    string
+		expected TValue
+	}{
+		"string": {"foo", NewTstring("foo")},
+		"binary": {"\xca\xfe", NewTBinary([]byte{0xca, 0xfe})},
+	}
+
+	for title, c := range cases {
+		t.Run(title, func(t *testing.T) {
+			// prepare
+			// JSON protocol replaces invalid UTF-8 of strings
+			iprot := thrift.NewTBinaryProtocolConf(thrift.NewTMemoryBuffer(), nil)
+			cxt := context.Background()
+			checkError(t, iprot.WriteString(cxt, c.value))
+			checkError(t, iprot.Flush(cxt))
+
+			// do
+			actual, err := ReadStringOrBinary(cxt, iprot)
+			checkError(t, err)
+
+			// verify
+			assertTrue(t, "equals", actual.Equals(&c.expected))
+		})
+	}
+}
@@ -41,6 +41,10 @@ func (p TBool) TType() thrift.TType {
 	return thrift.BOOL
 }
 
+func (p TBool) Export() any {
+	return p.value
+}
+
 func ReadBool(cxt context.Context, iprot thrift.TProtocol) (TValue, error) {
 	v, err := iprot.ReadBool(cxt)
 	if err != nil {
@@ -0,0 +1,57 @@
+package thrift
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+// TByte is Thrift `byte` (`i8`), which is a signed 8-bit integer.
+type TByte struct {
+	value int8
+}
+
+func NewTByte(v int8) TByte {
+	return TByte{value: v}
+}
+
+func (p TByte) Equals(other *TValue) bool {
+	o, ok := (*other).(TByte)
+	if !ok {
+		return false
+	}
+	return p.value == o.value
+}
+
+// See [Thrift IDL protocol spec]
+//
+//	<field> ::= <field-begin> <field-data> <field-end>
+//	<field-data> ::= I8
+//
+// [Thrift IDL protocol spec]: https://github.com/apache/thrift/blob/eec0b584e657e4250e22f3fd492858d632e2aa7b/doc/specs/thrift-protocol-spec.md
+func (p TByte) WriteFieldData(cxt context.Context, oprot thrift.TProtocol) (err error) {
+	if err = oprot.WriteByte(cxt, p.value); err != nil {
+		err = thrift.PrependError(fmt.Sprintf("%T.id (1) field write error: ", p), err)
+		return
+	}
+	return
+}
+
+func (p TByte) TType() thrift.TType {
+	return thrift.BYTE
+}
+
+func (p TByte) Export() any {
+	return p.value
+}
+
+func ReadByte(cxt context.Context, iprot thrift.TProtocol) (TValue, error) {
+	v, err := iprot.ReadByte(cxt)
+	if err != nil {
+		return nil, thrift.PrependError("error while reading byte: ", err)
+	}
+
+	res := NewTByte(v)
+	return res, nil
+}
@@ -0,0 +1,106 @@
+package thrift
+
+import (
+	"context"
+	"testing"
+)
+
+func TestEquals_TByte_equals(t *testing.T) {
+	// prepare
+	a := NewTByte(-3)
+	var b TValue = NewTByte(-3)
+	expected := true
+
+	// do
+	actual := a.Equals(&b)
+
+	// verify
+	assert(t, "", actual, expected)
+}
+
+func TestEquals_TByte_not_equals(t *testing.T) {
+	// prepare
+	a := NewTByte(-3)
+	var b TValue = NewTByte(3)
+	expected := false
+
+	// do
+	actual := a.Equals(&b)
+
+	// verify
+	assert(t, "", actual, expected)
+}
+
+func TestEquals_TByte_other_type(t *testing.T) {
+	// prepare
+	a := NewTByte(-3)
+	var b TValue = NewTI16(-3)
+	expected := false
+
+	// do
+	actual := a.Equals(&b)
+
+	// verify
+	assert(t, "", actual, expected)
+}
+
+func TestWriteFieldData_TByte(t *testing.T) {
+	// prepare
+	oprot := setupProtocol(t)
+	cxt := context.Background()
+	value := NewTByte(-3)
+	var expected int8 = -3
+
+	// do
+	err := value.WriteFieldData(cxt, oprot)
+	checkError(t, err)
+
+	// verify
+	oprot.Flush(cxt)
+	actual, err := oprot.ReadByte(cxt)
+	checkError(t, err)
+	assert(t, "", actual, expected)
+}
+
+func TestReadByte(t *testing.T) {
+	// prepare
+	iprot := setupProtocol(t)
+	cxt := context.Background()
+	checkError(
+		t,
+		iprot.WriteByte(cxt, -3),
+	)
+	checkError(
+		t,
+		iprot.Flush(cxt),
+	)
+
+	// do
+	actual, err := ReadByte(cxt, iprot)
+	checkError(t, err)
+
+	// verify
+	a, ok := actual.(TByte)
+	assertTrue(t, "cast to TByte", ok)
+	assert(t, "result", a.value, -3)
+}
+
+func TestReadByte_InvalidType(t *testing.T) {
+	// prepare
+	iprot := setupProtocol(t)
+	cxt := context.Background()
+	checkError(
+		t,
+		iprot.WriteString(cxt, "foo"),
+	)
+	checkError(
+		t,
+		iprot.Flush(cxt),
+	)
+
+	// do
+	_, err := ReadByte(cxt, iprot)
+
+	// verify
+	assertTrue(t, "error expected", err != nil)
+}
@@ -1,16 +1,50 @@
 package thrift
 
+import (
+	"time"
+
+	"github.com/grafana/sobek"
+	"go.k6.io/k6/metrics"
+)
+
+// TCallResult is a result of a call returned by `client.call()` in JavaScript.
 type TCallResult struct {
 	body TValue
 	err error
+	// method is the called method name.
+	method string
+	// seqID is a sequence ID of the message sent by the call. This is 0 when the call failed before sending.
+	seqID int32
+	// duration is time spent in the whole call.
+	duration time.Duration
+	// errKind is a kind of err, which is empty on success.
+	errKind string
+	// errCode is a code of err in errKind, which is empty on success or when the kind has no code.
+	errCode string
+	// headers is response headers. This is nil unless the protocol supports headers or the transport is "http".
+	headers map[string]string
+	// statusCode is HTTP status of the response. This is 0 unless the transport is "http".
+	statusCode int
+	// reqSize is bytes sent by the call.
+	reqSize int
+	// respSize is bytes received by the call.
+	respSize int
+	// exception is an exception thrown by the call. This is nil unless the call failed with an exception.
+	exception *TException
+	// timings is time spent in each phase of the call. This is nil when the call failed before connecting.
+	timings *ttimings
+	// rt is runtime of the VU, which creates ArrayBuffer of binary in the body. This is nil out of k6.
+	rt *sobek.Runtime
 }
 
 func NewTCallResult(body *TValue, err error) *TCallResult {
 	switch {
+	case body == nil && err != nil:
+		return &TCallResult{body: nil, err: err, errKind: errorKindUnknown}
 	case body == nil:
 		return &TCallResult{body: nil, err: err}
 	case err != nil:
-		return &TCallResult{body: nil, err: err}
+		return &TCallResult{body: nil, err: err, errKind: errorKindUnknown}
 	default:
 		return &TCallResult{body: *body, err: err}
 	}
@@ -19,3 +53,69 @@ func NewTCallResult(body *TValue, err error) *TCallResult {
 func (r *TCallResult) IsSuccess() bool {
 	return r.err == nil
 }
+
+// Body returns the returned value as a plain value navigable in JavaScript, or nil when the call failed.
+// Struct is an object whose keys are field IDs, map is an object, list is an array, and binary is ArrayBuffer.
+//
+//	res.body()["1"]  // field 1 of returned struct
+func (r *TCallResult) Body() any {
+	return toJS(r.rt, export(r.body))
+}
+
+// Error returns a message of the error, or empty string on success.
+func (r *TCallResult) Error() string {
+	if r.err == nil {
+		return ""
+	}
+	return r.err.Error()
+}
+
+// Method returns the called method name.
+func (r *TCallResult) Method() string {
+	return r.method
+}
+
+// SeqId returns the sequence ID of the message sent by the call. This is 0 when the call failed before sending.
+func (r *TCallResult) SeqId() int32 {
+	return r.seqID
+}
+
+// Duration returns time spent in the whole call in milliseconds, which is the same as `thrift_req_duration`.
+func (r *TCallResult) Duration() float64 {
+	return metrics.D(r.duration)
+}
+
+// Headers returns response headers, or nil when there are no headers.
+// These are THeader headers with "header" protocol, or HTTP response headers with "http" transport.
+func (r *TCallResult) Headers() map[string]string {
+	return r.headers
+}
+
+// Status returns HTTP status of the response, or 0 when the transport is not "http" or no response is received.
+func (r *TCallResult) Status() int {
+	return r.statusCode
+}
+
+// Exception returns an exception thrown by the call, or nil when there is no exception.
+// Value of declared exception is converted for JavaScript the same as [TCallResult.Body].
+func (r *TCallResult) Exception() *TException {
+	if r.exception == nil || r.exception.Value == nil {
+		return r.exception
+	}
+	e := *r.exception
+	e.JSValue = toJS(r.rt, export(e.Value))
+	return &e
+}
+
+// ErrorKind returns a kind of error, "timeout", "transport", "protocol", "application", "declared_exception", "http_status",
+// "canceled" or "unknown". This is empty on success.
+func (r *TCallResult) ErrorKind() string {
+	return r.errKind
+}
+
+// ErrorCode returns a code of error in its kind, such as "TIMED_OUT" of "timeout", "NOT_OPEN" of "transport",
+// "UNKNOWN_METHOD" of "application", field ID of "declared_exception" or "503" of "http_status".
+// This is empty on success, or for "canceled" and "unknown".
+func (r *TCallResult) ErrorCode() string {
+	return r.errCode
+}
@@ -0,0 +1,151 @@
+package thrift
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"go.k6.io/k6/js/modulestest"
+)
+
+func TestNewTCallResult_errorKind(t *testing.T) {
+	body := TValue(NewTstring("body"))
+
+	assert(t, "success", NewTCallResult(&body, nil).ErrorKind(), "")
+	assert(t, "failure", NewTCallResult(nil, fmt.Errorf("error")).ErrorKind(), "unknown")
+}
+
+func TestTCallResult_exceptionInJS(t *testing.T) {
+	// prepare
+	rt := modulestest.NewRuntime(t)
+	exception := newDeclaredException(1, NewTStruct(&map[TStructField]TValue{
+		*NewTStructField(1, "message"): NewTstring("not found"),
+		*NewTStructField(2, "data"):    NewTBinary([]byte{0xca, 0xfe}),
+	}))
+	result := NewTCallResult(nil, exception)
+	result.exception = exception
+	result.rt = rt.VU.Runtime()
+	checkError(t, rt.VU.Runtime().Set("declared", result))
+	checkError(t, rt.VU.Runtime().Set("success", NewTCallResult(nil, nil)))
+
+	// do
+	v, err := rt.VU.Runtime().RunString(`
+		const e = declared.exception();
+		e?.id === 1 && e.kind === "declared" && e.value["1"] === "not found" && e.value["2"] instanceof ArrayBuffer &&
+			success.exception()?.id === undefined
+	`)
+	checkError(t, err)
+
+	// verify
+	assertTrue(t, "exception in JS", v.ToBoolean())
+}
+
+func TestNewApplicationException(t *testing.T) {
+	// do
+	actual := newApplicationException(thrift.NewTApplicationException(thrift.UNKNOWN_METHOD, "unknown method"))
+
+	// verify
+	assert(t, "kind", actual.Kind, "application")
+	assert(t, "type", actual.Type, int32(thrift.UNKNOWN_METHOD))
+	assert(t, "message", actual.Message, "unknown method")
+}
+
+func TestTCallResult_accessorsInJS(t *testing.T) {
+	// prepare
+	rt := modulestest.NewRuntime(t)
+	body := TValue(NewTStruct(&map[TStructField]TValue{
+		*NewTStructField(1, "content"): NewTstring("content"),
+		*NewTStructField(2, "tags"): NewTMap(thrift.STRING, thrift.BOOL, &map[TValue]TValue{
+			NewTstring("tag"): NewTBool(true),
+		}),
+		*NewTStructField(3, "features"): NewTList(&[]TValue{NewTEnum(1), NewTEnum(2)}, thrift.I32),
+	}))
+	result := NewTCallResult(&body, nil)
+	result.method = "messageCall"
+	result.seqID = 3
+	result.duration = 1500 * time.Microsecond
+	checkError(t, rt.VU.Runtime().Set("r", result))
+
+	// do
+	v, err := rt.VU.Runtime().RunString(`[
+		r.body()[1] === "content",
+		r.body()[2]["tag"] === true,
+		r.body()[3][1] === 2,
+		r.error() === "",
+		r.errorKind() === "",
+		r.errorCode() === "",
+		r.method() === "messageCall",
+		r.seqId() === 3,
+		r.duration() === 1.5,
+	]`)
+	checkError(t, err)
+
+	// verify
+	for i, actual := range v.Export().([]any) {
+		assertTrue(t, fmt.Sprintf("accessor %d", i), actual.(bool))
+	}
+}
+
+func TestTCallResult_binaryBodyInJS(t *testing.T) {
+	// prepare
+	rt := modulestest.NewRuntime(t)
+	body := TValue(NewTStruct(&map[TStructField]TValue{
+		*NewTStructField(1, "data"):   NewTBinary([]byte{0xca, 0xfe}),
+		*NewTStructField(2, "chunks"): NewTList(&[]TValue{NewTBinary([]byte{0x01})}, thrift.STRING),
+	}))
+	result := NewTCallResult(&body, nil)
+	result.rt = rt.VU.Runtime()
+	checkError(t, rt.VU.Runtime().Set("r", result))
+
+	// do
+	v, err := rt.VU.Runtime().RunString(`[
+		r.body()[1] instanceof ArrayBuffer,
+		new Uint8Array(r.body()[1])[0] === 0xca,
+		r.body()[1].byteLength === 2,
+		new Uint8Array(r.body()[2][0])[0] === 1,
+	]`)
+	checkError(t, err)
+
+	// verify
+	var actual []bool
+	checkError(t, rt.VU.Runtime().ExportTo(v, &actual))
+	for i, a := range actual {
+		assertTrue(t, fmt.Sprintf("assertion %d", i), a)
+	}
+}
+
+func TestTCallResult_failureAccessors(t *testing.T) {
+	// do
+	actual := NewTCallResult(nil, fmt.Errorf("connection refused"))
+
+	// verify
+	assertTrue(t, "body", actual.Body() == nil)
+	assert(t, "error", actual.Error(), "connection refused")
+	assert(t, "errorKind", actual.ErrorKind(), "unknown")
+}
+
+func TestNewTCallResultFromResponse_void(t *testing.T) {
+	empty := NewTResponse()
+	withException := NewTResponse()
+	withException.Add(1, NewTStruct(&map[TStructField]TValue{}))
+
+	cases := map[string]struct {
+		res     *TResponse
+		void    bool
+		success bool
+	}{
+		"void":           {empty, true, true},
+		"not void":       {empty, false, false},
+		"void exception": {withException, true, false},
+	}
+
+	for title, c := range cases {
+		// do
+		actual := newTCallResultFromResponse(context.Background(), c.res, c.void, nil, 0)
+
+		// verify
+		assert(t, title, actual.IsSuccess(), c.success)
+	}
+}
@@ -0,0 +1,621 @@
+package thrift
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/grafana/sobek"
+	"go.k6.io/k6/js/modules"
+	"go.k6.io/k6/js/promises"
+)
+
+const (
+	defaultProtocol  = protocolBinary
+	defaultTransport = transportHTTP
+	defaultReuse     = reusePerVU
+
+	// bufferSize is a buffer size of "buffered" transport, which is the same as Thrift's other language implementations.
+	bufferSize = 8_192
+)
+
+// Names of protocol given as `protocol` option.
+const (
+	protocolBinary     = "binary"
+	protocolCompact    = "compact"
+	protocolJSON       = "json"
+	protocolSimpleJSON = "simplejson"
+	// protocolHeader uses THeaderProtocol, which wraps transport with THeaderTransport.
+	protocolHeader = "header"
+)
+
+var protocols = []string{protocolBinary, protocolCompact, protocolJSON, protocolSimpleJSON, protocolHeader}
+
+// contentTypes is HTTP Content-Type for each protocol, which is used for content negotiation
+// on servers such as Armeria. simplejson is not a Thrift format negotiated by them, so it is plain JSON.
+var contentTypes = map[string]string{
+	protocolBinary:     "application/x-thrift; protocol=TBINARY",
+	protocolCompact:    "application/x-thrift; protocol=TCOMPACT",
+	protocolJSON:       "application/x-thrift; protocol=TJSON",
+	protocolSimpleJSON: "application/json",
+}
+
+// Names of transport given as `transport` option.
+const (
+	// transportHTTP uses HTTP (or HTTPS) as transport.
+	transportHTTP = "http"
+	// transportSocket uses raw TCP socket as transport.
+	transportSocket = "socket"
+	// transportFramed uses TCP socket wrapped by TFramedTransport.
+	transportFramed = "framed"
+	// transportBuffered uses TCP socket wrapped by TBufferedTransport.
+	transportBuffered = "buffered"
+)
+
+// TClientOptions is options for `thrift.newClient()` in JavaScript.
+//
+//	const client = thrift.newClient({
+//	  url: "http://127.0.0.1:8080/thrift",
+//	  protocol: "binary",
+//	  transport: "http",
+//	  timeout: "10s",
+//	  tls: { insecureSkipVerify: false },
+//	});
+type TClientOptions struct {
+	// URL is an endpoint of Thrift service.
+	// This is `http(s)://host:port/path` for "http" transport, or `tcp(s)://host:port` for socket transports.
+	URL string `js:"url"`
+	// Protocol is a name of Thrift protocol, "binary", "compact", "json", "simplejson" or "header". Default is "binary".
+	// "simplejson" is write-only, so it can be used only with CallOneway.
+	// "header" can be used only with "socket" or "buffered" transport.
+	Protocol string `js:"protocol"`
+	// Transport is a name of Thrift transport, "http", "socket", "framed" or "buffered". Default is "http".
+	Transport string `js:"transport"`
+	// Timeout is a timeout of each call including connecting, sending and receiving. This is a duration string
+	// such as "10s" parsed by [time.ParseDuration]. No timeout when empty.
+	Timeout string `js:"timeout"`
+	// ConnectTimeout is a timeout of connecting to the server including TLS handshake. No timeout when empty.
+	ConnectTimeout string `js:"connectTimeout"`
+	// ReadTimeout is a timeout of each read from socket. This is a timeout of waiting for response headers for
+	// "http" transport. No timeout when empty.
+	ReadTimeout string `js:"readTimeout"`
+	// WriteTimeout is a timeout of each write to socket. This has no effect on "http" transport. No timeout when empty.
+	WriteTimeout string `js:"writeTimeout"`
+	// TLS is TLS options used when the endpoint requires TLS.
+	TLS TTLSOptions `js:"tls"`
+	// HTTP is options of HTTP client used by "http" transport.
+	HTTP THTTPOptions `js:"http"`
+	// Service is a service name registered to TMultiplexedProcessor in server. Multiplexing is disabled when empty.
+	Service string `js:"service"`
+	// Headers is headers sent with every call. These are THeader headers with "header" protocol, or HTTP request
+	// headers with "http" transport. This can't be used with the other protocols over socket transports.
+	Headers map[string]string `js:"headers"`
+	// Reuse is a connection reuse strategy, "per-call", "per-iteration" or "per-vu". Default is "per-vu".
+	Reuse string `js:"reuse"`
+	// ReconnectEvery closes a connection after it is used for the number of calls. Disabled when 0.
+	ReconnectEvery int `js:"reconnectEvery"`
+	// VoidMethods is names of `void` methods, whose empty result is success.
+	VoidMethods []string `js:"voidMethods"`
+	// Responses is hints of return values by method names, which tell i32 from enum and string from binary.
+	Responses map[string]*TTypeHint `js:"responses"`
+}
+
+// TCallOptions is options of each call, which is the last argument of `client.call()` in JavaScript.
+// Zero value of each option means the one of [TClientOptions] is used.
+//
+//	client.call("simpleCall", req, { service: "UserService", timeout: "500ms" });
+type TCallOptions struct {
+	// Timeout overrides [TClientOptions.Timeout].
+	Timeout string `js:"timeout"`
+	// Service overrides [TClientOptions.Service].
+	Service string `js:"service"`
+	// Headers is added to [TClientOptions.Headers]. Value of the same key is overridden.
+	Headers map[string]string `js:"headers"`
+	// Void is true when the method is `void`. The method is also `void` when it is in [TClientOptions.VoidMethods].
+	Void bool `js:"void"`
+	// Response overrides a hint of the return value in [TClientOptions.Responses].
+	Response *TTypeHint `js:"response"`
+}
+
+// TClient is a Thrift client bound to a single endpoint, created by `thrift.newClient()` in JavaScript.
+type TClient struct {
+	url       *url.URL
+	protocol  string
+	transport string
+	timeout   time.Duration
+	tlsConfig *tls.Config
+	service   string
+	headers   map[string]string
+
+	connectTimeout time.Duration
+	readTimeout    time.Duration
+	writeTimeout   time.Duration
+
+	reuse          string
+	reconnectEvery int
+	voidMethods    []string
+	responses      map[string]*TTypeHint
+
+	httpOpts   THTTPOptions
+	httpClient *http.Client
+	// vuOnce applies TLS options of k6 at the first call in VU context.
+	vuOnce sync.Once
+	// vu is VU which the client belongs to. This is nil when the client is created out of k6.
+	vu      modules.VU
+	metrics *tmetrics
+
+	// seqID is the last sequence ID of messages sent by the client.
+	seqID atomic.Int32
+
+	mu sync.Mutex
+	// idle is connections which are not used by any call now.
+	idle []*tconn
+}
+
+func NewTClient(opts *TClientOptions) (*TClient, error) {
+	if opts.URL == "" {
+		return nil, fmt.Errorf("url is required")
+	}
+	u, err := url.Parse(opts.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url %q: %w", opts.URL, err)
+	}
+
+	protocol := opts.Protocol
+	if protocol == "" {
+		protocol = defaultProtocol
+	}
+	if !slices.Contains(protocols, protocol) {
+		return nil, fmt.Errorf("unsupported protocol %q", protocol)
+	}
+
+	transport := opts.Transport
+	if transport == "" {
+		transport = defaultTransport
+	}
+	switch transport {
+	case transportHTTP:
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return nil, fmt.Errorf("url scheme must be http or https for %s transport: %q", transport, opts.URL)
+		}
+	case transportSocket, transportFramed, transportBuffered:
+		if (u.Scheme != "tcp" && u.Scheme != "tcps") || u.Host == "" {
+			return nil, fmt.Errorf("url must be tcp://host:port or tcps://host:port for %s transport: %q", transport, opts.URL)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported transport %q", transport)
+	}
+	if protocol == protocolHeader && transport != transportSocket && transport != transportBuffered {
+		return nil, fmt.Errorf("%s protocol requires socket or buffered transport, but was %s", protocol, transport)
+	}
+	if len(opts.Headers) > 0 && !supportsHeaders(protocol, transport) {
+		return nil, fmt.Errorf("headers requires header protocol or http transport, but was %s protocol and %s transport", protocol, transport)
+	}
+
+	reuse := opts.Reuse
+	if reuse == "" {
+		reuse = defaultReuse
+	}
+	if !slices.Contains(reuseStrategies, reuse) {
+		return nil, fmt.Errorf("unsupported reuse %q", reuse)
+	}
+	if opts.ReconnectEvery < 0 {
+		return nil, fmt.Errorf("reconnectEvery must not be negative: %d", opts.ReconnectEvery)
+	}
+
+	timeout, err := parseTimeout("timeout", opts.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	connectTimeout, err := parseTimeout("connectTimeout", opts.ConnectTimeout)
+	if err != nil {
+		return nil, err
+	}
+	readTimeout, err := parseTimeout("readTimeout", opts.ReadTimeout)
+	if err != nil {
+		return nil, err
+	}
+	writeTimeout, err := parseTimeout("writeTimeout", opts.WriteTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := newTLSConfig(&opts.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, method := range slices.Sorted(maps.Keys(opts.Responses)) {
+		if err := opts.Responses[method].validate("responses." + method); err != nil {
+			return nil, err
+		}
+	}
+
+	c := &TClient{
+		url:       u,
+		protocol:  protocol,
+		transport: transport,
+		timeout:   timeout,
+		tlsConfig: tlsConfig,
+		service:   opts.Service,
+		headers:   opts.Headers,
+
+		connectTimeout: connectTimeout,
+		readTimeout:    readTimeout,
+		writeTimeout:   writeTimeout,
+
+		reuse:          reuse,
+		reconnectEvery: opts.ReconnectEvery,
+		voidMethods:    opts.VoidMethods,
+		responses:      opts.Responses,
+		httpOpts:       opts.HTTP,
+	}
+	c.httpClient, err = newHTTPClient(u, &opts.HTTP, tlsConfig, c.dialContext, connectTimeout, readTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// parseTimeout parses value of timeout option named name. This returns 0 when value is empty.
+func parseTimeout(name, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", name, value, err)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("%s must not be negative: %q", name, value)
+	}
+	return d, nil
+}
+
+// Call calls method with req. opts can be nil.
+func (c *TClient) Call(method string, req *TRequest, opts *TCallOptions) *TCallResult {
+	return c.call(method, req, opts, false)
+}
+
+// CallOneway sends req to oneway method, and doesn't wait for any reply. This is `client.callOneway()` in JavaScript.
+// The result is success without body when req is sent. opts can be nil.
+func (c *TClient) CallOneway(method string, req *TRequest, opts *TCallOptions) *TCallResult {
+	return c.call(method, req, opts, true)
+}
+
+func (c *TClient) call(method string, req *TRequest, opts *TCallOptions, oneway bool) *TCallResult {
+	service := c.service
+	if opts != nil && opts.Service != "" {
+		service = opts.Service
+	}
+
+	start := time.Now()
+	result := c.invoke(method, service, req, opts, oneway)
+	result.method = method
+	result.duration = time.Since(start)
+	if c.vu != nil {
+		result.rt = c.vu.Runtime()
+	}
+	c.pushMetrics(method, service, oneway, result)
+	return result
+}
+
+// invoke makes a call to method of service. Service of opts is ignored, which is given as service.
+func (c *TClient) invoke(method, service string, req *TRequest, opts *TCallOptions, oneway bool) *TCallResult {
+	if !oneway && c.protocol == protocolSimpleJSON {
+		// TSimpleJSONProtocol is write-only, which can't read any reply
+		return NewTCallResult(nil, fmt.Errorf("%s protocol can be used only with callOneway, since it can't read replies", c.protocol))
+	}
+	headers := maps.Clone(c.headers)
+	var callHeaders map[string]string
+	timeout := c.timeout
+	void := slices.Contains(c.voidMethods, method)
+	hint := c.responses[method]
+	if opts != nil {
+		void = void || opts.Void
+		if opts.Response != nil {
+			if err := opts.Response.validate("response"); err != nil {
+				return NewTCallResult(nil, err)
+			}
+			hint = opts.Response
+		}
+		if opts.Timeout != "" {
+			t, err := parseTimeout("timeout", opts.Timeout)
+			if err != nil {
+				return NewTCallResult(nil, err)
+			}
+			timeout = t
+		}
+		if len(opts.Headers) > 0 && !supportsHeaders(c.protocol, c.transport) {
+			return NewTCallResult(nil, fmt.Errorf("headers requires header protocol or http transport, but was %s protocol and %s transport", c.protocol, c.transport))
+		}
+		if headers == nil {
+			headers = make(map[string]string)
+		}
+		maps.Copy(headers, opts.Headers)
+		callHeaders = opts.Headers
+	}
+
+	cxt, cancel := c.newContext(timeout)
+	defer cancel()
+
+	conn, err := c.acquire(cxt)
+	if err != nil {
+		result := NewTCallResult(nil, err)
+		result.errKind, result.errCode = classifyError(cxt, err, 0)
+		return result
+	}
+	conn.counter.reset()
+	// blocking IO of socket doesn't see context, so it is interrupted when the call is canceled or timed out
+	stop := func() bool { return true }
+	if conn.sock != nil {
+		stop = context.AfterFunc(cxt, conn.sock.interrupt)
+	}
+
+	timings := &ttimings{}
+	trace := &thttpTrace{}
+	httpRes := &thttpResponse{}
+	restoreHeaders := func() {}
+	if conn.sock != nil {
+		if conn.calls == 0 {
+			// opened by this call
+			timings.connecting = conn.sock.connecting
+			timings.tlsHandshaking = conn.sock.tlsHandshaking
+		}
+	} else {
+		cxt = httptrace.WithClientTrace(cxt, trace.clientTrace())
+		cxt = withHTTPResponse(cxt, httpRes)
+		if c.isLastCall(conn.calls + 1) {
+			// net/http closes only the TCP connection used by this request, which must not be reused by
+			// the reuse strategy
+			callHeaders = maps.Clone(callHeaders)
+			if callHeaders == nil {
+				callHeaders = make(map[string]string, 1)
+			}
+			callHeaders["Connection"] = "close"
+		}
+		// client headers are set when the connection is created
+		restoreHeaders = setHTTPHeaders(conn.http, callHeaders)
+	}
+
+	pf := c.newProtocolFactory()
+	iprot := pf.GetProtocol(conn.transport)
+	oprot := iprot
+	if c.protocol != protocolHeader {
+		// THeaderProtocol must be the same instance for input and output, but the others are not
+		oprot = pf.GetProtocol(conn.transport)
+	}
+	if hp, ok := iprot.(*thrift.THeaderProtocol); ok {
+		cxt = setTHeaders(cxt, hp, headers)
+	}
+	if service != "" {
+		// method name is prefixed with `service:`
+		oprot = thrift.NewTMultiplexedProtocol(oprot, service)
+	}
+	tclient := thrift.NewTStandardClient(iprot, oprot)
+	seqID := c.seqID.Add(1)
+
+	// send and receive are separated from TStandardClient.Call to measure each phase
+	var res *TResponse
+	var respHeaders map[string]string
+	start := time.Now()
+	if oneway {
+		err = sendOneway(cxt, oprot, seqID, method, req)
+	} else {
+		err = tclient.Send(cxt, oprot, seqID, method, req)
+	}
+	sent := time.Now()
+	timings.sending = sent.Sub(start)
+	if err == nil && !oneway {
+		res = NewTResponseWithHint(hint)
+		err = tclient.Recv(cxt, iprot, seqID, method, res)
+		timings.setReceived(sent, time.Now(), conn.counter)
+		if hp, ok := iprot.(*thrift.THeaderProtocol); ok {
+			respHeaders = hp.GetReadHeaders()
+		}
+	}
+	if conn.sock == nil {
+		trace.apply(timings)
+		respHeaders = httpRes.headers()
+		restoreHeaders()
+	}
+	// interrupt has been started when stop fails, and it may not have finished yet. The connection is closed
+	// not to be reused, since it would fail the next call with the deadline set by interrupt.
+	interrupting := !stop()
+	written, read := conn.counter.written, conn.counter.read
+	if interrupting {
+		c.closeConn(conn)
+	} else {
+		c.release(conn, err)
+	}
+	result := newTCallResultFromResponse(cxt, res, void, err, httpRes.statusCode)
+	result.headers = respHeaders
+	result.statusCode = httpRes.statusCode
+	result.reqSize = written
+	result.respSize = read
+	result.timings = timings
+	result.seqID = seqID
+	return result
+}
+
+// CallAsync calls method with req in background, and returns a promise resolved with [TCallResult].
+// This is `client.callAsync()` in JavaScript, and is available only in k6. opts can be nil.
+func (c *TClient) CallAsync(method string, req *TRequest, opts *TCallOptions) (*sobek.Promise, error) {
+	if c.vu == nil {
+		return nil, fmt.Errorf("callAsync is available only in k6")
+	}
+
+	promise, resolve, _ := promises.New(c.vu)
+	go func() {
+		// failure of the call is not rejection, but a result the same as `client.call()`
+		resolve(c.Call(method, req, opts))
+	}()
+	return promise, nil
+}
+
+// newContext returns context of a call, which is done when the VU is done or timeout has passed.
+// timeout is disabled when it is 0.
+func (c *TClient) newContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	cxt := context.Background()
+	if c.vu != nil && c.vu.Context() != nil {
+		cxt = c.vu.Context()
+	}
+	if timeout > 0 {
+		return context.WithTimeout(cxt, timeout)
+	}
+	return context.WithCancel(cxt)
+}
+
+// sendOneway writes ONEWAY message of method with req. Server never replies to it.
+func sendOneway(cxt context.Context, oprot thrift.TProtocol, seqID int32, method string, req *TRequest) error {
+	if err := oprot.WriteMessageBegin(cxt, method, thrift.ONEWAY, seqID); err != nil {
+		return err
+	}
+	if err := req.Write(cxt, oprot); err != nil {
+		return err
+	}
+	if err := oprot.WriteMessageEnd(cxt); err != nil {
+		return err
+	}
+	return oprot.Flush(cxt)
+}
+
+// newTCallResultFromResponse returns the result of a call, whose error is classified by [classifyError].
+// res is nil for oneway call. Empty res is success when the method is void.
+// statusCode is HTTP status of the response, or 0 when the call is not over HTTP.
+func newTCallResultFromResponse(cxt context.Context, res *TResponse, void bool, err error, statusCode int) *TCallResult {
+	result := newTCallResultOf(res, void, err)
+	if !result.IsSuccess() {
+		result.errKind, result.errCode = classifyError(cxt, result.err, statusCode)
+	}
+	return result
+}
+
+func newTCallResultOf(res *TResponse, void bool, err error) *TCallResult {
+	if err != nil {
+		slog.Error(fmt.Sprintf("ERROR calling RPC: %v", err))
+		result := NewTCallResult(nil, err)
+		var appErr thrift.TApplicationException
+		if errors.As(err, &appErr) {
+			result.exception = newApplicationException(appErr)
+		}
+		return result
+	}
+	if res == nil {
+		return NewTCallResult(nil, nil)
+	}
+	body := res.values[0]
+	if body == nil {
+		if exception := res.exception(); exception != nil {
+			result := NewTCallResult(nil, exception)
+			result.exception = exception
+			return result
+		}
+		if void {
+			return NewTCallResult(nil, nil)
+		}
+		// the same as the generated code for a missing result
+		return NewTCallResult(nil, thrift.NewTApplicationException(thrift.MISSING_RESULT, "Empty body"))
+	}
+
+	return NewTCallResult(&body, nil)
+}
+
+// setTHeaders sets headers written by THeaderProtocol.
+// TStandardClient sets headers in context to THeaderProtocol, but it doesn't when the protocol is wrapped
+// by TMultiplexedProtocol. So headers are set to the protocol directly as well.
+func setTHeaders(cxt context.Context, hp *thrift.THeaderProtocol, headers map[string]string) context.Context {
+	keys := slices.Sorted(maps.Keys(headers))
+	for _, k := range keys {
+		cxt = thrift.SetHeader(cxt, k, headers[k])
+		hp.SetWriteHeader(k, headers[k])
+	}
+	return thrift.SetWriteHeaderList(cxt, keys)
+}
+
+// setHTTPHeaders sets headers of requests sent by trans, and returns a function restoring the previous headers.
+// THttpClient.SetHeader adds a value, so the previous value is deleted first.
+func setHTTPHeaders(trans *thrift.THttpClient, headers map[string]string) (restore func()) {
+	prev := make(map[string]string, len(headers))
+	for k, v := range headers {
+		prev[k] = trans.GetHeader(k)
+		trans.DelHeader(k)
+		trans.SetHeader(k, v)
+	}
+	return func() {
+		for k, v := range prev {
+			trans.DelHeader(k)
+			if v != "" {
+				trans.SetHeader(k, v)
+			}
+		}
+	}
+}
+
+// supportsHeaders reports whether headers can be sent with protocol over transport.
+func supportsHeaders(protocol, transport string) bool {
+	return protocol == protocolHeader || transport == transportHTTP
+}
+
+func (c *TClient) newProtocolFactory() thrift.TProtocolFactory {
+	cfg := &thrift.TConfiguration{}
+	switch c.protocol {
+	case protocolCompact:
+		return thrift.NewTCompactProtocolFactoryConf(cfg)
+	case protocolJSON:
+		return thrift.NewTJSONProtocolFactory()
+	case protocolSimpleJSON:
+		return thrift.NewTSimpleJSONProtocolFactoryConf(cfg)
+	case protocolHeader:
+		return thrift.NewTHeaderProtocolFactoryConf(cfg)
+	default:
+		return thrift.NewTBinaryProtocolFactoryConf(cfg)
+	}
+}
+
+// newConn returns a new connection, which is not opened yet.
+func (c *TClient) newConn() (*tconn, error) {
+	if c.transport == transportHTTP {
+		trans, err := thrift.NewTHttpClientWithOptions(c.url.String(), thrift.THttpClientOptions{Client: c.httpClient})
+		if err != nil {
+			return nil, err
+		}
+		httpTrans := trans.(*thrift.THttpClient)
+		httpTrans.DelHeader("Content-Type")
+		httpTrans.SetHeader("Content-Type", contentTypes[c.protocol])
+		setHTTPHeaders(httpTrans, c.headers)
+		counter := &tcounter{TTransport: trans}
+		return &tconn{transport: counter, http: httpTrans, httpClient: c.httpClient, counter: counter}, nil
+	}
+
+	sock := &tsocket{
+		addr:           c.url.Host,
+		dialer:         c.dialer(),
+		connectTimeout: c.connectTimeout,
+		readTimeout:    c.readTimeout,
+		writeTimeout:   c.writeTimeout,
+	}
+	if c.url.Scheme == "tcps" {
+		sock.tlsConfig = c.tlsConfig
+	}
+	counter := &tcounter{TTransport: sock}
+	conn := &tconn{transport: counter, sock: sock, counter: counter}
+	switch c.transport {
+	case transportFramed:
+		conn.transport = thrift.NewTFramedTransportConf(counter, &thrift.TConfiguration{})
+	case transportBuffered:
+		conn.transport = thrift.NewTBufferedTransport(counter, bufferSize)
+	}
+	return conn, nil
+}
@@ -0,0 +1,213 @@
+package thrift
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+func TestNewTClient_defaults(t *testing.T) {
+	// prepare
+	opts := TClientOptions{URL: "http://127.0.0.1:8080/thrift"}
+
+	// do
+	actual, err := NewTClient(&opts)
+	checkError(t, err)
+
+	// verify
+	assert(t, "url", actual.url.String(), "http://127.0.0.1:8080/thrift")
+	assert(t, "protocol", actual.protocol, "binary")
+	assert(t, "transport", actual.transport, "http")
+	assert(t, "timeout", actual.timeout, time.Duration(0))
+	assert(t, "insecureSkipVerify", actual.tlsConfig.InsecureSkipVerify, false)
+}
+
+func TestNewTClient_options(t *testing.T) {
+	// prepare
+	opts := TClientOptions{
+		URL:       "https://example.com:9090/api",
+		Protocol:  "binary",
+		Transport: "http",
+		Timeout:   "1500ms",
+		TLS:       TTLSOptions{InsecureSkipVerify: true},
+
+		ConnectTimeout: "1s",
+		ReadTimeout:    "2s",
+		WriteTimeout:   "3s",
+	}
+
+	// do
+	actual, err := NewTClient(&opts)
+	checkError(t, err)
+
+	// verify
+	assert(t, "url", actual.url.String(), "https://example.com:9090/api")
+	assert(t, "timeout", actual.timeout, 1500*time.Millisecond)
+	assert(t, "connectTimeout", actual.connectTimeout, time.Second)
+	assert(t, "readTimeout", actual.readTimeout, 2*time.Second)
+	assert(t, "writeTimeout", actual.writeTimeout, 3*time.Second)
+	assert(t, "http readTimeout", actual.httpClient.Transport.(*thttpTransport).RoundTripper.(*http.Transport).ResponseHeaderTimeout, 2*time.Second)
+	assert(t, "insecureSkipVerify", actual.tlsConfig.InsecureSkipVerify, true)
+}
+
+func TestNewTClient_socketTransports(t *testing.T) {
+	for _, transport := range []string{"socket", "framed", "buffered"} {
+		// prepare
+		opts := TClientOptions{URL: "tcp://127.0.0.1:9090", Transport: transport}
+
+		// do
+		actual, err := NewTClient(&opts)
+		checkError(t, err)
+
+		// verify
+		assert(t, "transport", actual.transport, transport)
+		assert(t, "host", actual.url.Host, "127.0.0.1:9090")
+	}
+
+	// TLS
+	opts := TClientOptions{URL: "tcps://127.0.0.1:9090", Transport: "framed"}
+	actual, err := NewTClient(&opts)
+	checkError(t, err)
+	assert(t, "scheme", actual.url.Scheme, "tcps")
+}
+
+func TestNewTClient_protocols(t *testing.T) {
+	cases := map[string]thrift.TProtocolFactory{
+		"binary":     thrift.NewTBinaryProtocolFactoryConf(nil),
+		"compact":    thrift.NewTCompactProtocolFactoryConf(nil),
+		"json":       thrift.NewTJSONProtocolFactory(),
+		"simplejson": thrift.NewTSimpleJSONProtocolFactoryConf(nil),
+	}
+
+	for protocol, expected := range cases {
+		// prepare
+		opts := TClientOptions{URL: "http://127.0.0.1:8080/thrift", Protocol: protocol}
+
+		// do
+		actual, err := NewTClient(&opts)
+		checkError(t, err)
+
+		// verify
+		assert(t, "protocol", actual.protocol, protocol)
+		assert(t, protocol, fmt.Sprintf("%T", actual.newProtocolFactory()), fmt.Sprintf("%T", expected))
+	}
+}
+
+func TestNewTClient_header(t *testing.T) {
+	// prepare
+	opts := TClientOptions{
+		URL:       "tcp://127.0.0.1:9090",
+		Protocol:  "header",
+		Transport: "socket",
+		Headers:   map[string]string{"client-name": "k6"},
+	}
+
+	// do
+	actual, err := NewTClient(&opts)
+	checkError(t, err)
+
+	// verify
+	assert(t, "protocol", actual.protocol, "header")
+	assert(t, "header", actual.headers["client-name"], "k6")
+	assert(t, "factory", fmt.Sprintf("%T", actual.newProtocolFactory()), fmt.Sprintf("%T", thrift.NewTHeaderProtocolFactoryConf(nil)))
+}
+
+func TestNewTClient_httpHeaders(t *testing.T) {
+	// prepare
+	opts := TClientOptions{
+		URL:     "http://127.0.0.1:8080/thrift",
+		Headers: map[string]string{"Authorization": "Bearer token"},
+	}
+	client, err := NewTClient(&opts)
+	checkError(t, err)
+
+	// do
+	actual, err := client.newConn()
+	checkError(t, err)
+
+	// verify
+	assert(t, "authorization", actual.http.GetHeader("Authorization"), "Bearer token")
+	assert(t, "content type", actual.http.GetHeader("Content-Type"), "application/x-thrift; protocol=TBINARY")
+}
+
+func TestSetHTTPHeaders(t *testing.T) {
+	// prepare
+	trans, err := thrift.NewTHttpClient("http://127.0.0.1:8080/thrift")
+	checkError(t, err)
+	httpTrans := trans.(*thrift.THttpClient)
+	httpTrans.SetHeader("Authorization", "Bearer client")
+
+	// do
+	restore := setHTTPHeaders(httpTrans, map[string]string{"Authorization": "Bearer call", "X-Request-Id": "abc"})
+
+	// verify
+	assert(t, "authorization", httpTrans.GetHeader("Authorization"), "Bearer call")
+	assert(t, "request id", httpTrans.GetHeader("X-Request-Id"), "abc")
+	restore()
+	assert(t, "restored authorization", httpTrans.GetHeader("Authorization"), "Bearer client")
+	assert(t, "restored request id", httpTrans.GetHeader("X-Request-Id"), "")
+}
+
+func TestNewTClient_invalid(t *testing.T) {
+	cases := map[string]TClientOptions{
+		"no url":           {},
+		"invalid scheme":   {URL: "ftp://127.0.0.1:8080"},
+		"invalid timeout":  {URL: "http://127.0.0.1:8080", Timeout: "ten seconds"},
+		"negative timeout": {URL: "http://127.0.0.1:8080", Timeout: "-1s"},
+		"invalid connect":  {URL: "http://127.0.0.1:8080", ConnectTimeout: "1"},
+		"invalid read":     {URL: "http://127.0.0.1:8080", ReadTimeout: "1"},
+		"invalid write":    {URL: "http://127.0.0.1:8080", WriteTimeout: "1"},
+		"invalid tls":      {URL: "https://127.0.0.1:8080", TLS: TTLSOptions{MinVersion: "ssl3"}},
+		"unknown proto":    {URL: "http://127.0.0.1:8080", Protocol: "unknown"},
+		"unknown trans":    {URL: "http://127.0.0.1:8080", Transport: "unknown"},
+		"http for socket":  {URL: "http://127.0.0.1:8080", Transport: "framed"},
+		"tcp for http":     {URL: "tcp://127.0.0.1:8080", Transport: "http"},
+		"header on http":   {URL: "http://127.0.0.1:8080", Protocol: "header"},
+		"header framed":    {URL: "tcp://127.0.0.1:8080", Protocol: "header", Transport: "framed"},
+		"headers binary":   {URL: "tcp://127.0.0.1:8080", Transport: "socket", Headers: map[string]string{"k": "v"}},
+	}
+
+	for title, opts := range cases {
+		// do
+		_, err := NewTClient(&opts)
+
+		// verify
+		assertTrue(t, title, err != nil)
+	}
+}
+
+func TestSendOneway(t *testing.T) {
+	// prepare
+	buf := thrift.NewTMemoryBuffer()
+	prot := thrift.NewTBinaryProtocolConf(buf, nil)
+	req := NewTRequestWithValue(&map[int16]TValue{1: NewTstring("ID")})
+
+	// do
+	err := sendOneway(context.Background(), prot, 3, "emit", req)
+	checkError(t, err)
+
+	// verify
+	name, typeID, seqID, err := prot.ReadMessageBegin(context.Background())
+	checkError(t, err)
+	assert(t, "name", name, "emit")
+	assert(t, "seqID", seqID, int32(3))
+	assertTrue(t, "oneway", typeID == thrift.ONEWAY)
+}
+
+func TestTClient_simpleJSONReply(t *testing.T) {
+	// prepare
+	client, err := NewTClient(&TClientOptions{URL: "tcp://127.0.0.1:1", Transport: "socket", Protocol: "simplejson"})
+	checkError(t, err)
+
+	// do
+	actual := client.Call("getUser", nil, nil)
+
+	// verify
+	assertTrue(t, "failure", !actual.IsSuccess())
+	// rejected before connecting
+	assert(t, "kind", actual.errKind, errorKindUnknown)
+}
@@ -0,0 +1,147 @@
+package thrift
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+// Names of connection reuse strategy given as `reuse` option.
+const (
+	// reusePerCall opens a new connection for every call, and closes it after the call.
+	reusePerCall = "per-call"
+	// reusePerIteration reuses a connection within an iteration of VU, and closes it when the iteration ends.
+	reusePerIteration = "per-iteration"
+	// reusePerVU reuses a connection across iterations of VU until the test ends.
+	reusePerVU = "per-vu"
+)
+
+var reuseStrategies = []string{reusePerCall, reusePerIteration, reusePerVU}
+
+// tconn is an opened transport to Thrift server.
+type tconn struct {
+	transport thrift.TTransport
+	// sock is the underlying socket of transport. This is nil for "http" transport.
+	sock *tsocket
+	// http is the underlying THttpClient of transport. This is nil for socket transports.
+	http *thrift.THttpClient
+	// httpClient is http.Client of http, which pools TCP connections shared by connections of the client.
+	// This is nil for socket transports.
+	httpClient *http.Client
+	// counter counts bytes of each call.
+	counter *tcounter
+	// iteration is the VU iteration when the connection is opened.
+	iteration int64
+	// calls is the number of calls made with the connection.
+	calls int
+}
+
+// acquire returns an idle connection, or opens a new one when there is no idle connection available.
+// Opening a connection is canceled when cxt is done.
+func (c *TClient) acquire(cxt context.Context) (*tconn, error) {
+	c.applyVUOptions()
+	iteration := c.iteration()
+
+	c.mu.Lock()
+	for len(c.idle) > 0 {
+		conn := c.idle[len(c.idle)-1]
+		c.idle = c.idle[:len(c.idle)-1]
+		// connections are closed at the end of iterations, but they may be left without events of k6
+		if c.reuse == reusePerIteration && conn.iteration != iteration {
+			c.closeConn(conn)
+			if conn.httpClient != nil {
+				// idle TCP connections in the pool are opened by previous iterations as well
+				conn.httpClient.CloseIdleConnections()
+			}
+			continue
+		}
+		c.mu.Unlock()
+		return conn, nil
+	}
+	c.mu.Unlock()
+
+	conn, err := c.newConn()
+	if err != nil {
+		slog.Error(fmt.Sprintf("ERROR while getting transport: %v", err))
+		return nil, err
+	}
+	if conn.sock != nil {
+		// wrapping transports just open the socket
+		err = conn.sock.OpenContext(cxt)
+	} else {
+		err = conn.transport.Open()
+	}
+	if err != nil {
+		slog.Error(fmt.Sprintf("ERROR while opening transport: %v", err))
+		return nil, err
+	}
+	conn.iteration = iteration
+	return conn, nil
+}
+
+// release puts back conn used by a call which ended with err, so that it can be reused by the next call.
+// conn is closed instead when it must not be reused by the reuse strategy, or when err may break the
+// state of the connection.
+func (c *TClient) release(conn *tconn, err error) {
+	conn.calls++
+
+	if c.isLastCall(conn.calls) || !isReusable(err) || (conn.sock != nil && conn.sock.isInterrupted()) {
+		c.closeConn(conn)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.idle = append(c.idle, conn)
+}
+
+// Close closes all idle connections, including idle TCP connections pooled by http.Client.
+// This is `client.close()` in JavaScript. Client can still be used after that, and opens a new connection.
+func (c *TClient) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, conn := range c.idle {
+		c.closeConn(conn)
+		if conn.httpClient != nil {
+			conn.httpClient.CloseIdleConnections()
+		}
+	}
+	c.idle = nil
+}
+
+// isLastCall reports whether the connection must be closed after the calls-th call by the reuse strategy.
+func (c *TClient) isLastCall(calls int) bool {
+	return c.reuse == reusePerCall || (c.reconnectEvery > 0 && calls >= c.reconnectEvery)
+}
+
+// closeConn closes conn. THttpClient doesn't own TCP connections, which are pooled in http.Client shared by
+// other connections. net/http closes TCP connections broken by failed requests, and the last call by the reuse
+// strategy sends `Connection: close` to close its TCP connection, so the pool is not flushed here.
+func (c *TClient) closeConn(conn *tconn) {
+	if err := conn.transport.Close(); err != nil {
+		slog.Warn(fmt.Sprintf("WARN while closing transport: %v", err))
+	}
+}
+
+func (c *TClient) iteration() int64 {
+	if c.vu == nil || c.vu.State() == nil {
+		return -1
+	}
+	return c.vu.State().Iteration
+}
+
+// isReusable reports whether the connection can be reused after a call ended with err.
+// TApplicationException is sent by server as a valid response, but the others such as transport
+// error may leave unread data in the connection.
+func isReusable(err error) bool {
+	if err == nil {
+		return true
+	}
+	var appErr thrift.TApplicationException
+	return errors.As(err, &appErr)
+}
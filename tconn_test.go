@@ -0,0 +1,163 @@
+package thrift
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"go.k6.io/k6/js/modulestest"
+	"go.k6.io/k6/lib"
+)
+
+// startListener starts a TCP listener which only counts accepted connections.
+func startListener(t *testing.T) (string, *atomic.Int32) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	checkError(t, err)
+	t.Cleanup(func() { l.Close() })
+
+	accepted := &atomic.Int32{}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			accepted.Add(1)
+			t.Cleanup(func() { conn.Close() })
+		}
+	}()
+	return "tcp://" + l.Addr().String(), accepted
+}
+
+// useConnections acquires and releases connections for calls times, and returns opened connection count.
+func useConnections(t *testing.T, client *TClient, calls int) int {
+	opened := map[*tconn]struct{}{}
+	for i := 0; i < calls; i++ {
+		conn, err := client.acquire(context.Background())
+		checkError(t, err)
+		opened[conn] = struct{}{}
+		client.release(conn, nil)
+	}
+	return len(opened)
+}
+
+func TestAcquire_reuse(t *testing.T) {
+	cases := map[string]struct {
+		reuse          string
+		reconnectEvery int
+		expected       int
+	}{
+		"per-call":        {"per-call", 0, 5},
+		"per-iteration":   {"per-iteration", 0, 1},
+		"per-vu":          {"per-vu", 0, 1},
+		"reconnect every": {"per-vu", 2, 3},
+	}
+
+	for title, c := range cases {
+		// prepare
+		url, _ := startListener(t)
+		client, err := NewTClient(&TClientOptions{URL: url, Transport: "socket", Reuse: c.reuse, ReconnectEvery: c.reconnectEvery})
+		checkError(t, err)
+
+		// do
+		actual := useConnections(t, client, 5)
+
+		// verify
+		assert(t, title, actual, c.expected)
+	}
+}
+
+func TestAcquire_perIteration(t *testing.T) {
+	// prepare
+	url, _ := startListener(t)
+	rt := modulestest.NewRuntime(t)
+	rt.MoveToVUContext(&lib.State{Iteration: 0})
+	client, err := NewTClient(&TClientOptions{URL: url, Transport: "socket", Reuse: "per-iteration"})
+	checkError(t, err)
+	client.vu = rt.VU
+
+	// do
+	first := useConnections(t, client, 3)
+	rt.VU.StateField.Iteration = 1
+	second := useConnections(t, client, 3)
+
+	// verify
+	assert(t, "first iteration", first, 1)
+	assert(t, "second iteration", second, 1)
+	assertTrue(t, "other connection", client.idle[0].iteration == 1)
+}
+
+func TestRelease_transportError(t *testing.T) {
+	// prepare
+	url, _ := startListener(t)
+	client, err := NewTClient(&TClientOptions{URL: url, Transport: "socket"})
+	checkError(t, err)
+	conn, err := client.acquire(context.Background())
+	checkError(t, err)
+
+	// do
+	client.release(conn, thrift.NewTTransportException(thrift.TIMED_OUT, "timeout"))
+
+	// verify
+	assert(t, "idle", len(client.idle), 0)
+	assertTrue(t, "closed", !conn.transport.IsOpen())
+}
+
+func TestRelease_applicationError(t *testing.T) {
+	// prepare
+	url, _ := startListener(t)
+	client, err := NewTClient(&TClientOptions{URL: url, Transport: "socket"})
+	checkError(t, err)
+	conn, err := client.acquire(context.Background())
+	checkError(t, err)
+
+	// do
+	client.release(conn, thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "internal error"))
+
+	// verify
+	assert(t, "idle", len(client.idle), 1)
+}
+
+func TestClose(t *testing.T) {
+	// prepare
+	url, _ := startListener(t)
+	client, err := NewTClient(&TClientOptions{URL: url, Transport: "socket"})
+	checkError(t, err)
+	conn, err := client.acquire(context.Background())
+	checkError(t, err)
+	client.release(conn, nil)
+
+	// do
+	client.Close()
+
+	// verify
+	assert(t, "idle", len(client.idle), 0)
+	assertTrue(t, "closed", !conn.transport.IsOpen())
+}
+
+func TestIsReusable(t *testing.T) {
+	assertTrue(t, "nil", isReusable(nil))
+	assertTrue(t, "application", isReusable(thrift.NewTApplicationException(thrift.UNKNOWN_METHOD, "")))
+	assertTrue(t, "transport", !isReusable(thrift.NewTTransportException(thrift.END_OF_FILE, "")))
+	assertTrue(t, "protocol", !isReusable(thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, errors.New(""))))
+}
+
+func TestRelease_interrupted(t *testing.T) {
+	// prepare
+	url, _ := startListener(t)
+	client, err := NewTClient(&TClientOptions{URL: url, Transport: "socket"})
+	checkError(t, err)
+	conn, err := client.acquire(context.Background())
+	checkError(t, err)
+	conn.sock.interrupt()
+
+	// do
+	client.release(conn, nil)
+
+	// verify
+	assert(t, "idle", len(client.idle), 0)
+	assertTrue(t, "closed", !conn.transport.IsOpen())
+}
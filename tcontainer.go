@@ -7,23 +7,53 @@ import (
 )
 
 func ReadContainerData(ttype thrift.TType, cxt context.Context, iprot thrift.TProtocol) (TValue, error) {
+	return ReadContainerDataWithHint(ttype, nil, cxt, iprot)
+}
+
+// ReadContainerDataWithHint reads field data of ttype decoded by hint, which can be nil.
+// The hint is ignored when it is not of ttype on the wire.
+// Without hints, i32 is read as [TI32], and string is read as [TBinary] only when it is not valid UTF-8.
+func ReadContainerDataWithHint(ttype thrift.TType, hint *TTypeHint, cxt context.Context, iprot thrift.TProtocol) (TValue, error) {
 	var tv TValue
 	var err error
 
+	hint = hint.of(ttype)
 	switch ttype {
-	// FIXME: i32 duplication for enum / int32. change the way to determine
 	case thrift.I32:
-		tv, err = ReadEnum(cxt, iprot)
+		if hint.is(hintEnum) {
+			tv, err = readEnum(cxt, iprot, hint.Enum)
+		} else {
+			tv, err = ReadI32(cxt, iprot)
+		}
+	case thrift.BYTE:
+		tv, err = ReadByte(cxt, iprot)
+	case thrift.I16:
+		tv, err = ReadI16(cxt, iprot)
+	case thrift.I64:
+		tv, err = ReadI64(cxt, iprot)
+	case thrift.DOUBLE:
+		tv, err = ReadDouble(cxt, iprot)
 	case thrift.STRING:
-		tv, err = ReadString(cxt, iprot)
+		switch {
+		case hint.is(hintString):
+			tv, err = ReadString(cxt, iprot)
+		case hint.is(hintBinary):
+			tv, err = ReadBinary(cxt, iprot)
+		default:
+			tv, err = ReadStringOrBinary(cxt, iprot)
+		}
+	case thrift.UUID:
+		tv, err = ReadUUID(cxt, iprot)
 	case thrift.BOOL:
 		tv, err = ReadBool(cxt, iprot)
 	case thrift.LIST:
-		tv, err = ReadList(cxt, iprot)
+		tv, err = readList(cxt, iprot, hint.elem())
+	case thrift.SET:
+		tv, err = readSet(cxt, iprot, hint.elem())
 	case thrift.MAP:
-		tv, err = ReadMap(cxt, iprot)
+		tv, err = readMap(cxt, iprot, hint.key(), hint.value())
 	case thrift.STRUCT:
-		tv, err = ReadStruct(cxt, iprot)
+		tv, err = readStruct(cxt, iprot, hint)
 	default:
 		err = iprot.Skip(cxt, ttype)
 	}
@@ -0,0 +1,51 @@
+package thrift
+
+import (
+	"time"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+// tcounter is a transport counting bytes written to and read from the wrapped transport, and time spent in reading.
+// This wraps the socket under framing for socket transports, and THttpClient for "http" transport,
+// so bytes of framing are counted but the ones of HTTP headers are not.
+type tcounter struct {
+	thrift.TTransport
+
+	// written is bytes written since the last reset.
+	written int
+	// read is bytes read since the last reset.
+	read int
+	// firstReading is time spent in the first read since the last reset, which waits for the first bytes.
+	firstReading time.Duration
+	// reading is time spent in all reads since the last reset.
+	reading time.Duration
+}
+
+var _ thrift.TTransport = (*tcounter)(nil)
+
+func (c *tcounter) Read(p []byte) (int, error) {
+	start := time.Now()
+	n, err := c.TTransport.Read(p)
+	elapsed := time.Since(start)
+	if c.reading == 0 {
+		c.firstReading = elapsed
+	}
+	c.reading += elapsed
+	c.read += n
+	return n, err
+}
+
+func (c *tcounter) Write(p []byte) (int, error) {
+	n, err := c.TTransport.Write(p)
+	c.written += n
+	return n, err
+}
+
+// reset resets counts and time, which is called when a call starts.
+func (c *tcounter) reset() {
+	c.written = 0
+	c.read = 0
+	c.firstReading = 0
+	c.reading = 0
+}
@@ -0,0 +1,26 @@
+package thrift
+
+import (
+	"testing"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+func TestTCounter(t *testing.T) {
+	// prepare
+	counter := &tcounter{TTransport: thrift.NewTMemoryBuffer()}
+
+	// do
+	_, err := counter.Write([]byte("hello"))
+	checkError(t, err)
+	_, err = counter.Read(make([]byte, 3))
+	checkError(t, err)
+
+	// verify
+	assert(t, "written", counter.written, 5)
+	assert(t, "read", counter.read, 3)
+
+	counter.reset()
+	assert(t, "written after reset", counter.written, 0)
+	assert(t, "read after reset", counter.read, 0)
+}
@@ -0,0 +1,56 @@
+package thrift
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+type TDouble struct {
+	value float64
+}
+
+func NewTDouble(v float64) TDouble {
+	return TDouble{value: v}
+}
+
+func (p TDouble) Equals(other *TValue) bool {
+	o, ok := (*other).(TDouble)
+	if !ok {
+		return false
+	}
+	return p.value == o.value
+}
+
+// See [Thrift IDL protocol spec]
+//
+//	<field> ::= <field-begin> <field-data> <field-end>
+//	<field-data> ::= DOUBLE
+//
+// [Thrift IDL protocol spec]: https://github.com/apache/thrift/blob/eec0b584e657e4250e22f3fd492858d632e2aa7b/doc/specs/thrift-protocol-spec.md
+func (p TDouble) WriteFieldData(cxt context.Context, oprot thrift.TProtocol) (err error) {
+	if err = oprot.WriteDouble(cxt, p.value); err != nil {
+		err = thrift.PrependError(fmt.Sprintf("%T.id (1) field write error: ", p), err)
+		return
+	}
+	return
+}
+
+func (p TDouble) TType() thrift.TType {
+	return thrift.DOUBLE
+}
+
+func (p TDouble) Export() any {
+	return p.value
+}
+
+func ReadDouble(cxt context.Context, iprot thrift.TProtocol) (TValue, error) {
+	v, err := iprot.ReadDouble(cxt)
+	if err != nil {
+		return nil, thrift.PrependError("error while reading double: ", err)
+	}
+
+	res := NewTDouble(v)
+	return res, nil
+}
@@ -0,0 +1,106 @@
+package thrift
+
+import (
+	"context"
+	"testing"
+)
+
+func TestEquals_TDouble_equals(t *testing.T) {
+	// prepare
+	a := NewTDouble(1.5)
+	var b TValue = NewTDouble(1.5)
+	expected := true
+
+	// do
+	actual := a.Equals(&b)
+
+	// verify
+	assert(t, "", actual, expected)
+}
+
+func TestEquals_TDouble_not_equals(t *testing.T) {
+	// prepare
+	a := NewTDouble(1.5)
+	var b TValue = NewTDouble(-1.5)
+	expected := false
+
+	// do
+	actual := a.Equals(&b)
+
+	// verify
+	assert(t, "", actual, expected)
+}
+
+func TestEquals_TDouble_other_type(t *testing.T) {
+	// prepare
+	a := NewTDouble(1.5)
+	var b TValue = NewTstring("1.5")
+	expected := false
+
+	// do
+	actual := a.Equals(&b)
+
+	// verify
+	assert(t, "", actual, expected)
+}
+
+func TestWriteFieldData_TDouble(t *testing.T) {
+	// prepare
+	oprot := setupProtocol(t)
+	cxt := context.Background()
+	value := NewTDouble(1.5)
+	var expected float64 = 1.5
+
+	// do
+	err := value.WriteFieldData(cxt, oprot)
+	checkError(t, err)
+
+	// verify
+	oprot.Flush(cxt)
+	actual, err := oprot.ReadDouble(cxt)
+	checkError(t, err)
+	assert(t, "", actual, expected)
+}
+
+func TestReadDouble(t *testing.T) {
+	// prepare
+	iprot := setupProtocol(t)
+	cxt := context.Background()
+	checkError(
+		t,
+		iprot.WriteDouble(cxt, 1.5),
+	)
+	checkError(
+		t,
+		iprot.Flush(cxt),
+	)
+
+	// do
+	actual, err := ReadDouble(cxt, iprot)
+	checkError(t, err)
+
+	// verify
+	a, ok := actual.(TDouble)
+	assertTrue(t, "cast to TDouble", ok)
+	assert(t, "result", a.value, 1.5)
+}
+
+func TestReadDouble_InvalidType(t *testing.T) {
+	// prepare
+	iprot := setupProtocol(t)
+	cxt := context.Background()
+	checkError(
+		t,
+		iprot.WriteString(cxt, "foo"),
+	)
+	checkError(
+		t,
+		iprot.Flush(cxt),
+	)
+
+	// do
+	_, err := ReadDouble(cxt, iprot)
+
+	// verify
+	assertTrue(t, "error expected", err != nil)
+}
@@ -7,14 +7,25 @@ import (
 	"github.com/apache/thrift/lib/go/thrift"
 )
 
+// TEnum is Thrift `enum`, which is i32 on the wire.
+// The name is known only when it is decoded with names of the enum by [TTypeHint].
 type TEnum struct {
 	value int32
+	name  string
+	// named is true when the enum is decoded with names, even if the value has no name.
+	named bool
 }
 
 func NewTEnum(v int32) TEnum {
 	return TEnum{value: v}
 }
 
+// NewTEnumWithName creates [TEnum] of value v named name.
+func NewTEnumWithName(v int32, name string) TEnum {
+	return TEnum{value: v, name: name, named: true}
+}
+
+// Equals returns true when other has the same value, regardless of the name.
 func (p TEnum) Equals(other *TValue) bool {
 	o, ok := (*other).(TEnum)
 	if !ok {
@@ -41,6 +52,15 @@ func (p TEnum) TType() thrift.TType {
 	return thrift.I32
 }
 
+// Export returns the value, or an object with `value` and `name` when it is decoded with names of the enum.
+// The name is empty when the value has no name.
+func (p TEnum) Export() any {
+	if !p.named {
+		return p.value
+	}
+	return map[string]any{"value": p.value, "name": p.name}
+}
+
 func ReadEnum(cxt context.Context, iproto thrift.TProtocol) (TValue, error) {
 	v, err := iproto.ReadI32(cxt)
 	if err != nil {
@@ -50,3 +70,17 @@ func ReadEnum(cxt context.Context, iproto thrift.TProtocol) (TValue, error) {
 	res := NewTEnum(v)
 	return res, nil
 }
+
+// readEnum reads enum named by names. The enum has no names when names is nil.
+func readEnum(cxt context.Context, iprot thrift.TProtocol, names map[int32]string) (TValue, error) {
+	if names == nil {
+		return ReadEnum(cxt, iprot)
+	}
+	v, err := iprot.ReadI32(cxt)
+	if err != nil {
+		return nil, thrift.PrependError("error while reading i32 field", err)
+	}
+
+	res := NewTEnumWithName(v, names[v])
+	return res, nil
+}
@@ -105,3 +105,29 @@ func TestReadEnum_InvalidType(t *testing.T) {
 	// verfiy
 	assertTrue(t, "error expected", err != nil)
 }
+
+func TestEquals_Enum_withName(t *testing.T) {
+	// prepare
+	a := NewTEnumWithName(1, "ONE")
+	var b TValue = NewTEnum(1)
+	expected := true
+
+	// do
+	actual := a.Equals(&b)
+
+	// verify
+	assert(t, "", actual, expected)
+}
+
+func TestExport_Enum(t *testing.T) {
+	// do
+	plain := NewTEnum(1).Export()
+	named := NewTEnumWithName(1, "ONE").Export().(map[string]any)
+	unnamed := NewTEnumWithName(3, "").Export().(map[string]any)
+
+	// verify
+	assert(t, "plain", plain.(int32), 1)
+	assert(t, "named value", named["value"].(int32), 1)
+	assert(t, "named name", named["name"].(string), "ONE")
+	assert(t, "unnamed name", unnamed["name"].(string), "")
+}
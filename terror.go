@@ -0,0 +1,141 @@
+package thrift
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+// Kinds of error of a call returned by [TCallResult.ErrorKind].
+const (
+	// errorKindTimeout is returned when timeout of the call, or connect, read or write timeout has passed.
+	errorKindTimeout = "timeout"
+	// errorKindTransport is returned for TTransportException other than timeout.
+	errorKindTransport = "transport"
+	// errorKindProtocol is returned for TProtocolException, such as invalid data in the response.
+	errorKindProtocol = "protocol"
+	// errorKindApplication is returned for TApplicationException replied by the server.
+	errorKindApplication = "application"
+	// errorKindDeclaredException is returned for an exception declared in `throws` of the method.
+	errorKindDeclaredException = "declared_exception"
+	// errorKindHTTPStatus is returned when HTTP server responds with status other than 200.
+	errorKindHTTPStatus = "http_status"
+	// errorKindCanceled is returned when the call is canceled because the VU is done.
+	errorKindCanceled = "canceled"
+	// errorKindUnknown is returned for the other errors, such as invalid options.
+	errorKindUnknown = "unknown"
+)
+
+// Names of TTransportException types returned by [TCallResult.ErrorCode].
+var transportErrorCodes = map[int]string{
+	thrift.UNKNOWN_TRANSPORT_EXCEPTION: "UNKNOWN",
+	thrift.NOT_OPEN:                    "NOT_OPEN",
+	thrift.ALREADY_OPEN:                "ALREADY_OPEN",
+	thrift.TIMED_OUT:                   "TIMED_OUT",
+	thrift.END_OF_FILE:                 "END_OF_FILE",
+}
+
+// Names of TProtocolException types returned by [TCallResult.ErrorCode].
+var protocolErrorCodes = map[int]string{
+	thrift.UNKNOWN_PROTOCOL_EXCEPTION: "UNKNOWN",
+	thrift.INVALID_DATA:               "INVALID_DATA",
+	thrift.NEGATIVE_SIZE:              "NEGATIVE_SIZE",
+	thrift.SIZE_LIMIT:                 "SIZE_LIMIT",
+	thrift.BAD_VERSION:                "BAD_VERSION",
+	thrift.NOT_IMPLEMENTED:            "NOT_IMPLEMENTED",
+	thrift.DEPTH_LIMIT:                "DEPTH_LIMIT",
+}
+
+// Names of TApplicationException types returned by [TCallResult.ErrorCode].
+var applicationErrorCodes = map[int32]string{
+	thrift.UNKNOWN_APPLICATION_EXCEPTION:  "UNKNOWN",
+	thrift.UNKNOWN_METHOD:                 "UNKNOWN_METHOD",
+	thrift.INVALID_MESSAGE_TYPE_EXCEPTION: "INVALID_MESSAGE_TYPE",
+	thrift.WRONG_METHOD_NAME:              "WRONG_METHOD_NAME",
+	thrift.BAD_SEQUENCE_ID:                "BAD_SEQUENCE_ID",
+	thrift.MISSING_RESULT:                 "MISSING_RESULT",
+	thrift.INTERNAL_ERROR:                 "INTERNAL_ERROR",
+	thrift.PROTOCOL_ERROR:                 "PROTOCOL_ERROR",
+	thrift.INVALID_TRANSFORM:              "INVALID_TRANSFORM",
+	thrift.INVALID_PROTOCOL:               "INVALID_PROTOCOL",
+	thrift.UNSUPPORTED_CLIENT_TYPE:        "UNSUPPORTED_CLIENT_TYPE",
+}
+
+// codeTimedOut is the code of "timeout" kind, which is the one of TTransportException.
+var codeTimedOut = transportErrorCodes[thrift.TIMED_OUT]
+
+// classifyError returns a kind and a code of err returned by a call with context cxt.
+// statusCode is HTTP status of the response, or 0 when the call is not over HTTP or has no response.
+// The code is a type of the thrift exception, a field ID of declared exception or HTTP status.
+func classifyError(cxt context.Context, err error, statusCode int) (kind, code string) {
+	if errors.Is(cxt.Err(), context.Canceled) {
+		// interrupted IO fails with timeout error as well
+		return errorKindCanceled, ""
+	}
+	if errors.Is(cxt.Err(), context.DeadlineExceeded) || isTimeout(err) {
+		return errorKindTimeout, codeTimedOut
+	}
+	if statusCode != 0 && statusCode != http.StatusOK {
+		return errorKindHTTPStatus, strconv.Itoa(statusCode)
+	}
+
+	var exception *TException
+	if errors.As(err, &exception) && exception.Kind == exceptionDeclared {
+		return errorKindDeclaredException, strconv.Itoa(int(exception.ID))
+	}
+	var appErr thrift.TApplicationException
+	if errors.As(err, &appErr) {
+		return errorKindApplication, codeOf(applicationErrorCodes, appErr.TypeId())
+	}
+	// TTransportException satisfies TProtocolException as well, so they are told by TExceptionType
+	var texc thrift.TException
+	if errors.As(err, &texc) {
+		switch texc.TExceptionType() {
+		case thrift.TExceptionTypeTransport:
+			if isDialError(err) {
+				// THttpClient wraps errors of connecting as UNKNOWN, but socket transports report them as NOT_OPEN
+				return errorKindTransport, transportErrorCodes[thrift.NOT_OPEN]
+			}
+			var transErr thrift.TTransportException
+			if errors.As(err, &transErr) {
+				return errorKindTransport, codeOf(transportErrorCodes, transErr.TypeId())
+			}
+		case thrift.TExceptionTypeProtocol:
+			var protoErr thrift.TProtocolException
+			if errors.As(err, &protoErr) {
+				return errorKindProtocol, codeOf(protocolErrorCodes, protoErr.TypeId())
+			}
+		}
+	}
+	return errorKindUnknown, ""
+}
+
+// codeOf returns a name of typeID in codes, or typeID itself when it is unknown.
+func codeOf[T int | int32](codes map[T]string, typeID T) string {
+	if code, ok := codes[typeID]; ok {
+		return code
+	}
+	return strconv.Itoa(int(typeID))
+}
+
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var transErr thrift.TTransportException
+	if errors.As(err, &transErr) && transErr.TypeId() == thrift.TIMED_OUT {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// isDialError reports whether err is caused by failure of connecting to the server.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
@@ -0,0 +1,59 @@
+package thrift
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"syscall"
+	"testing"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+func TestClassifyError(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	timedOut, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	background := context.Background()
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+
+	cases := map[string]struct {
+		cxt        context.Context
+		err        error
+		statusCode int
+		kind       string
+		code       string
+	}{
+		"canceled":          {canceled, thrift.NewTTransportException(thrift.TIMED_OUT, "timeout"), 0, "canceled", ""},
+		"deadline":          {timedOut, fmt.Errorf("EOF"), 0, "timeout", "TIMED_OUT"},
+		"transport timeout": {background, thrift.NewTTransportException(thrift.TIMED_OUT, "timeout"), 0, "timeout", "TIMED_OUT"},
+		"wrapped deadline":  {background, fmt.Errorf("wrapped: %w", context.DeadlineExceeded), 0, "timeout", "TIMED_OUT"},
+		"end of file":       {background, thrift.NewTTransportException(thrift.END_OF_FILE, "EOF"), 0, "transport", "END_OF_FILE"},
+		"prepended":         {background, thrift.PrependError("reading: ", thrift.NewTTransportException(thrift.NOT_OPEN, "closed")), 0, "transport", "NOT_OPEN"},
+		"protocol": {
+			background, thrift.NewTProtocolExceptionWithType(thrift.SIZE_LIMIT, fmt.Errorf("too large")), 0, "protocol", "SIZE_LIMIT",
+		},
+		"application": {
+			background, thrift.NewTApplicationException(thrift.UNKNOWN_METHOD, "unknown method"), 0, "application", "UNKNOWN_METHOD",
+		},
+		"declared":    {background, newDeclaredException(2, NewTstring("not found")), 0, "declared_exception", "2"},
+		"http status": {background, thrift.NewTTransportException(thrift.UNKNOWN_TRANSPORT_EXCEPTION, "HTTP Response code: 503"), 503, "http_status", "503"},
+		"http dial": {
+			background, thrift.NewTTransportExceptionFromError(&url.Error{Op: "Post", URL: "http://127.0.0.1:1", Err: dialErr}), 0,
+			"transport", "NOT_OPEN",
+		},
+		"http ok": {background, thrift.NewTTransportException(thrift.END_OF_FILE, "EOF"), 200, "transport", "END_OF_FILE"},
+		"other":   {background, fmt.Errorf("invalid"), 0, "unknown", ""},
+	}
+
+	for title, c := range cases {
+		// do
+		kind, code := classifyError(c.cxt, c.err, c.statusCode)
+
+		// verify
+		assert(t, title+" kind", kind, c.kind)
+		assert(t, title+" code", code, c.code)
+	}
+}
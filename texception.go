@@ -0,0 +1,52 @@
+package thrift
+
+import (
+	"fmt"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+// Kinds of [TException].
+const (
+	// exceptionDeclared is an exception declared in `throws` of the method in IDL.
+	exceptionDeclared = "declared"
+	// exceptionApplication is TApplicationException sent by server, such as unknown method.
+	exceptionApplication = "application"
+)
+
+// TException is an exception thrown by a call, which is returned by `result.exception()` in JavaScript.
+//
+//	check(res, { "not found": (r) => r.exception()?.id === 1 && r.exception().value["1"] === "ID" });
+type TException struct {
+	// Kind is "declared" or "application".
+	Kind string `js:"kind"`
+	// ID is a field ID of the declared exception in result struct, which is the ID in `throws` of IDL.
+	// This is 0 for TApplicationException.
+	ID int16 `js:"id"`
+	// Value is the decoded struct of the declared exception. This is nil for TApplicationException.
+	Value TValue `js:"-"`
+	// JSValue is Value converted the same as [TCallResult.Body], which is `value` in JavaScript.
+	// This is set by [TCallResult.Exception].
+	JSValue any `js:"value"`
+	// Type is a type code of TApplicationException such as 1 (UNKNOWN_METHOD). This is 0 for declared exception.
+	Type int32 `js:"type"`
+	// Message is a message of TApplicationException. This is empty for declared exception.
+	Message string `js:"message"`
+}
+
+var _ error = (*TException)(nil)
+
+func newDeclaredException(id int16, value TValue) *TException {
+	return &TException{Kind: exceptionDeclared, ID: id, Value: value}
+}
+
+func newApplicationException(err thrift.TApplicationException) *TException {
+	return &TException{Kind: exceptionApplication, Type: err.TypeId(), Message: err.Error()}
+}
+
+func (e *TException) Error() string {
+	if e.Kind == exceptionApplication {
+		return fmt.Sprintf("application exception (type %d): %s", e.Type, e.Message)
+	}
+	return fmt.Sprintf("declared exception (id %d): %v", e.ID, e.Value)
+}
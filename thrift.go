@@ -1,118 +1,129 @@
 package thrift
 
 import (
-	"context"
-	"crypto/tls"
-	"fmt"
-	"log/slog"
-	"strconv"
+	"errors"
+	"sync"
 
-	"github.com/apache/thrift/lib/go/thrift"
+	"go.k6.io/k6/event"
+	"go.k6.io/k6/js/common"
 	"go.k6.io/k6/js/modules"
 )
 
 func init() {
-	modules.Register("k6/x/thrift", new(TModule))
+	modules.Register("k6/x/thrift", new(TRootModule))
 	modules.Register("k6/x/thrift/ttypes", new(TTypes))
 }
 
-type TModule struct{}
+// TRootModule is the global module of `k6/x/thrift`, which creates [TModule] for each VU.
+type TRootModule struct{}
 
-func (m *TModule) Echo() *TCallResult {
-	host := "127.0.0.1"
-	port := 8080
-	path := "/thrift"
-	method := "simpleCall"
+var _ modules.Module = (*TRootModule)(nil)
 
-	tf := thrift.NewTHttpClientTransportFactory("http://" + host + ":" + strconv.Itoa(port) + path)
-	cfg := thrift.TConfiguration{
-		TLSConfig: &tls.Config{
-			InsecureSkipVerify: true,
-		},
-	}
-	sock := thrift.NewTSocketConf("127.0.0.1:8080", &cfg)
-	transport, err := tf.GetTransport(sock)
-	if err != nil {
-		slog.Error(fmt.Sprintf("ERROR while getting transport: %v", err))
-		return NewTCallResult(nil, err)
-	}
-	pf := thrift.NewTBinaryProtocolFactoryConf(&cfg)
-	iprot := pf.GetProtocol(transport)
-	oprot := pf.GetProtocol(transport)
-	tclient := thrift.NewTStandardClient(iprot, oprot)
-	defer transport.Close()
-
-	err = transport.Open()
+func (*TRootModule) NewModuleInstance(vu modules.VU) modules.Instance {
+	m, err := registerMetrics(vu.InitEnv().Registry)
 	if err != nil {
-		slog.Error(fmt.Sprintf("ERROR while opening transport: %v", err))
-		return NewTCallResult(nil, err)
+		common.Throw(vu.Runtime(), err)
 	}
+	return &TModule{vu: vu, metrics: m}
+}
 
-	values := make(map[int16]TValue)
-	values[1] = NewTstring("ID")
-	req := NewTRequestWithValue(&values)
-	res := NewTResponse()
-
-	cxt := context.Background()
-	_, err = tclient.Call(cxt, method, req, res)
-	if err != nil {
-		slog.Error(fmt.Sprintf("ERROR calling RPC: %v", err))
-		return NewTCallResult(nil, err)
-	}
+// TModule is `k6/x/thrift` module for a VU.
+type TModule struct {
+	vu      modules.VU
+	metrics *tmetrics
+
+	subscribeOnce sync.Once
+	mu            sync.Mutex
+	// clients is clients created in the VU, which are closed when the test ends.
+	clients []*TClient
+	// iterEndSub is a subscription ID of IterEnd event of the VU.
+	iterEndSub uint64
+}
 
-	slog.Info(fmt.Sprintf("Response: %v", res))
+var _ modules.Instance = (*TModule)(nil)
 
-	body := res.values[0]
-	if body == nil {
-		return NewTCallResult(nil, fmt.Errorf("Empty body"))
+func (m *TModule) Exports() modules.Exports {
+	return modules.Exports{
+		Named: map[string]any{
+			"newClient": m.NewClient,
+		},
 	}
-	
-	return NewTCallResult(&body, nil)
 }
 
-func (m *TModule) Call(method string, req *TRequest) *TCallResult {
-	host := "127.0.0.1"
-	port := 8080
-	path := "/thrift"
-
-	tf := thrift.NewTHttpClientTransportFactory("http://" + host + ":" + strconv.Itoa(port) + path)
-	cfg := thrift.TConfiguration{
-		TLSConfig: &tls.Config{
-			InsecureSkipVerify: true,
-		},
+// NewClient creates [TClient] with given options. This is `thrift.newClient()` in JavaScript.
+// This can be called only in init context, since clients are kept until the test ends.
+func (m *TModule) NewClient(opts TClientOptions) (*TClient, error) {
+	if m.vu.State() != nil {
+		return nil, errors.New("thrift.newClient() can be called only in init context")
 	}
-	sock := thrift.NewTSocketConf("127.0.0.1:8080", &cfg)
-	transport, err := tf.GetTransport(sock)
+	client, err := NewTClient(&opts)
 	if err != nil {
-		slog.Error(fmt.Sprintf("ERROR: %v", err))
-		return NewTCallResult(nil, err)
+		return nil, err
 	}
-	pf := thrift.NewTBinaryProtocolFactoryConf(&cfg)
-	iprot := pf.GetProtocol(transport)
-	oprot := pf.GetProtocol(transport)
-	tclient := thrift.NewTStandardClient(iprot, oprot)
-	defer transport.Close()
+	client.vu = m.vu
+	client.metrics = m.metrics
+
+	m.mu.Lock()
+	m.clients = append(m.clients, client)
+	m.mu.Unlock()
+	m.subscribeOnce.Do(func() {
+		// iterEndSub is set before it is unsubscribed with the global exit event
+		m.closeIterationConnsOnIterEnd()
+		m.closeClientsOnTestEnd()
+	})
+
+	return client, nil
+}
 
-	err = transport.Open()
-	if err != nil {
-		slog.Error(fmt.Sprintf("ERROR: %v", err))
-		return NewTCallResult(nil, err)
+// closeClientsOnTestEnd closes connections of all clients when the test ends.
+func (m *TModule) closeClientsOnTestEnd() {
+	global := m.vu.Events().Global
+	if global == nil {
+		return
 	}
 
-	res := NewTResponse()
+	sid, ch := global.Subscribe(event.TestEnd, event.Exit)
+	go func() {
+		for e := range ch {
+			m.mu.Lock()
+			for _, client := range m.clients {
+				client.Close()
+			}
+			m.mu.Unlock()
+
+			e.Done()
+			if e.Type == event.Exit {
+				global.Unsubscribe(sid)
+				if local := m.vu.Events().Local; local != nil && m.iterEndSub != 0 {
+					local.Unsubscribe(m.iterEndSub)
+				}
+			}
+		}
+	}()
+}
 
-	cxt := context.Background()
-	_, err = tclient.Call(cxt, method, req, res)
-	if err != nil {
-		slog.Error(fmt.Sprintf("ERROR calling RPC: %v", err))
-		return NewTCallResult(nil, err)
-	}
-	body := res.values[0]
-	if body == nil {
-		return NewTCallResult(nil, fmt.Errorf("Empty body"))
+// closeIterationConnsOnIterEnd closes connections of clients with "per-iteration" reuse strategy when each iteration
+// ends, so that they are not kept during sleep between iterations.
+func (m *TModule) closeIterationConnsOnIterEnd() {
+	local := m.vu.Events().Local
+	if local == nil {
+		return
 	}
 
-	slog.Info(fmt.Sprintf("Response: %v", res.values))
-	
-	return NewTCallResult(&body, nil)
+	// local events have no exit event, so this is unsubscribed with the global exit event
+	var ch <-chan *event.Event
+	m.iterEndSub, ch = local.Subscribe(event.IterEnd)
+	go func() {
+		for e := range ch {
+			m.mu.Lock()
+			for _, client := range m.clients {
+				if client.reuse == reusePerIteration {
+					client.Close()
+				}
+			}
+			m.mu.Unlock()
+
+			e.Done()
+		}
+	}()
 }
@@ -0,0 +1,171 @@
+package thrift
+
+import (
+	"context"
+	"testing"
+
+	"go.k6.io/k6/event"
+	"go.k6.io/k6/js/common"
+	"go.k6.io/k6/js/modulestest"
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/testutils"
+)
+
+func setupModule(t *testing.T) (*modulestest.Runtime, *TModule) {
+	rt := modulestest.NewRuntime(t)
+	m, ok := new(TRootModule).NewModuleInstance(rt.VU).(*TModule)
+	assertTrue(t, "module instance", ok)
+	checkError(t, rt.VU.Runtime().Set("thrift", m.Exports().Named))
+	return rt, m
+}
+
+func TestNewClient(t *testing.T) {
+	// prepare
+	rt, m := setupModule(t)
+
+	// do
+	v, err := rt.VU.Runtime().RunString(`
+		thrift.newClient({
+			url: "tcp://127.0.0.1:9090",
+			transport: "framed",
+			reuse: "per-iteration",
+			reconnectEvery: 10,
+		})
+	`)
+	checkError(t, err)
+
+	// verify
+	client, ok := v.Export().(*TClient)
+	assertTrue(t, "client", ok)
+	assert(t, "transport", client.transport, "framed")
+	assert(t, "reuse", client.reuse, "per-iteration")
+	assert(t, "reconnectEvery", client.reconnectEvery, 10)
+	assertTrue(t, "vu", client.vu == rt.VU)
+	assert(t, "clients", len(m.clients), 1)
+}
+
+func TestNewClient_responses(t *testing.T) {
+	// prepare
+	rt, _ := setupModule(t)
+
+	// do
+	v, err := rt.VU.Runtime().RunString(`
+		thrift.newClient({
+			url: "tcp://127.0.0.1:9090",
+			transport: "framed",
+			responses: {
+				enumCall: { type: "list", elem: { type: "enum", enum: { 1: "ONE" } } },
+				messageCall: { type: "struct", fields: { 2: { type: "binary" } } },
+			},
+		})
+	`)
+	checkError(t, err)
+
+	// verify
+	client, ok := v.Export().(*TClient)
+	assertTrue(t, "client", ok)
+	assert(t, "enum", client.responses["enumCall"].Elem.Enum[1], "ONE")
+	assert(t, "field", client.responses["messageCall"].Fields[2].Type, "binary")
+}
+
+func TestNewClient_invalid(t *testing.T) {
+	// prepare
+	rt, _ := setupModule(t)
+
+	// do
+	_, err := rt.VU.Runtime().RunString(`thrift.newClient({ url: "tcp://127.0.0.1:9090", reuse: "never" })`)
+
+	// verify
+	assertTrue(t, "error expected", err != nil)
+}
+
+func TestNewClient_vuContext(t *testing.T) {
+	// prepare
+	rt, _ := setupModule(t)
+	rt.MoveToVUContext(&lib.State{})
+
+	// do
+	_, err := rt.VU.Runtime().RunString(`thrift.newClient({ url: "tcp://127.0.0.1:9090", transport: "socket" })`)
+
+	// verify
+	assertTrue(t, "error expected", err != nil)
+}
+
+func TestNewClient_closeOnTestEnd(t *testing.T) {
+	// prepare
+	rt := modulestest.NewRuntime(t)
+	global := event.NewEventSystem(10, testutils.NewLogger(t))
+	rt.VU.EventsField = common.Events{Global: global, Local: event.NewEventSystem(10, testutils.NewLogger(t))}
+	m, ok := new(TRootModule).NewModuleInstance(rt.VU).(*TModule)
+	assertTrue(t, "module instance", ok)
+	url, _ := startListener(t)
+	client, err := m.NewClient(TClientOptions{URL: url, Transport: "socket"})
+	checkError(t, err)
+	conn, err := client.acquire(context.Background())
+	checkError(t, err)
+	client.release(conn, nil)
+
+	// do
+	wait := global.Emit(&event.Event{Type: event.TestEnd})
+	checkError(t, wait(context.Background()))
+
+	// verify
+	assert(t, "idle", len(client.idle), 0)
+	assertTrue(t, "closed", !conn.transport.IsOpen())
+}
+
+func TestCallAsync(t *testing.T) {
+	// prepare
+	rt, _ := setupModule(t)
+
+	// do
+	_, err := rt.RunOnEventLoop(`
+		const client = thrift.newClient({ url: "tcp://127.0.0.1:1", transport: "socket" });
+		client.callAsync("simpleCall", null).then((r) => { globalThis.result = r; });
+	`)
+	checkError(t, err)
+
+	// verify
+	result, ok := rt.VU.Runtime().Get("result").Export().(*TCallResult)
+	assertTrue(t, "result", ok)
+	assertTrue(t, "failure", !result.IsSuccess())
+}
+
+func TestCallAsync_withoutVU(t *testing.T) {
+	// prepare
+	client, err := NewTClient(&TClientOptions{URL: "tcp://127.0.0.1:1", Transport: "socket"})
+	checkError(t, err)
+
+	// do
+	_, err = client.CallAsync("simpleCall", nil, nil)
+
+	// verify
+	assertTrue(t, "error expected", err != nil)
+}
+
+func TestNewClient_closePerIterationOnIterEnd(t *testing.T) {
+	// prepare
+	rt := modulestest.NewRuntime(t)
+	local := event.NewEventSystem(10, testutils.NewLogger(t))
+	rt.VU.EventsField = common.Events{Global: event.NewEventSystem(10, testutils.NewLogger(t)), Local: local}
+	m, ok := new(TRootModule).NewModuleInstance(rt.VU).(*TModule)
+	assertTrue(t, "module instance", ok)
+	url, _ := startListener(t)
+	perIteration, err := m.NewClient(TClientOptions{URL: url, Transport: "socket", Reuse: "per-iteration"})
+	checkError(t, err)
+	perVU, err := m.NewClient(TClientOptions{URL: url, Transport: "socket"})
+	checkError(t, err)
+	for _, client := range []*TClient{perIteration, perVU} {
+		conn, err := client.acquire(context.Background())
+		checkError(t, err)
+		client.release(conn, nil)
+	}
+
+	// do
+	wait := local.Emit(&event.Event{Type: event.IterEnd})
+	checkError(t, wait(context.Background()))
+
+	// verify
+	assert(t, "per-iteration idle", len(perIteration.idle), 0)
+	assert(t, "per-vu idle", len(perVU.idle), 1)
+}
@@ -0,0 +1,167 @@
+package thrift
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http2"
+)
+
+// Defaults of [THTTPOptions], which are the same as http.DefaultTransport.
+const (
+	defaultMaxIdleConns    = 100
+	defaultIdleConnTimeout = 90 * time.Second
+)
+
+// THTTPOptions is options of HTTP client used by "http" transport, which is `http` of [TClientOptions].
+//
+//	http: {
+//	  maxIdleConns: 100,
+//	  maxIdleConnsPerHost: 10,
+//	  maxConnsPerHost: 10,
+//	  idleConnTimeout: "90s",
+//	  disableKeepAlives: false,
+//	  h2c: false,
+//	  proxy: "http://proxy.example.com:3128",
+//	}
+type THTTPOptions struct {
+	// MaxIdleConns is the maximum number of idle connections across all hosts. Default is 100.
+	MaxIdleConns int `js:"maxIdleConns"`
+	// MaxIdleConnsPerHost is the maximum number of idle connections per host. Go's default 2 is used when 0.
+	MaxIdleConnsPerHost int `js:"maxIdleConnsPerHost"`
+	// MaxConnsPerHost is the maximum number of connections per host including active ones. No limit when 0.
+	MaxConnsPerHost int `js:"maxConnsPerHost"`
+	// IdleConnTimeout is how long an idle connection is kept. This is a duration string such as "90s". Default is "90s".
+	IdleConnTimeout string `js:"idleConnTimeout"`
+	// DisableKeepAlives opens a new connection for every request.
+	DisableKeepAlives bool `js:"disableKeepAlives"`
+	// HTTP2 enables HTTP/2 negotiated by TLS ALPN for `https` url.
+	HTTP2 bool `js:"http2"`
+	// H2C uses HTTP/2 over cleartext with prior knowledge for `http` url, such as Armeria servers.
+	// Requests are multiplexed over a connection, so the other options except for IdleConnTimeout can't be used with this.
+	H2C bool `js:"h2c"`
+	// Proxy is a URL of HTTP proxy. Proxy of environment variables such as HTTP_PROXY is used when empty.
+	Proxy string `js:"proxy"`
+}
+
+// newHTTPClient returns http.Client of "http" transport to endpoint u, which dials connections by dial.
+// Timeout of a call is given by context of the request, so only timeout of TLS handshake and waiting for
+// response headers are set to the client.
+func newHTTPClient(
+	u *url.URL, opts *THTTPOptions, tlsConfig *tls.Config, dial func(context.Context, string, string) (net.Conn, error),
+	connectTimeout, readTimeout time.Duration,
+) (*http.Client, error) {
+	idleConnTimeout, err := parseTimeout("http.idleConnTimeout", opts.IdleConnTimeout)
+	if err != nil {
+		return nil, err
+	}
+	if idleConnTimeout == 0 {
+		idleConnTimeout = defaultIdleConnTimeout
+	}
+	if opts.MaxIdleConns < 0 || opts.MaxIdleConnsPerHost < 0 || opts.MaxConnsPerHost < 0 {
+		return nil, fmt.Errorf("http.maxIdleConns, http.maxIdleConnsPerHost and http.maxConnsPerHost must not be negative")
+	}
+	maxIdleConns := opts.MaxIdleConns
+	if maxIdleConns == 0 {
+		maxIdleConns = defaultMaxIdleConns
+	}
+	proxy := http.ProxyFromEnvironment
+	if opts.Proxy != "" {
+		proxyURL, err := url.Parse(opts.Proxy)
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid http.proxy %q", opts.Proxy)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+	if opts.H2C {
+		if u.Scheme != "http" {
+			return nil, fmt.Errorf("http.h2c requires http url, but was %q", u.String())
+		}
+		if opts.MaxIdleConns != 0 || opts.MaxIdleConnsPerHost != 0 || opts.MaxConnsPerHost != 0 ||
+			opts.DisableKeepAlives || opts.HTTP2 || opts.Proxy != "" {
+			return nil, fmt.Errorf("http.h2c can't be used with the other http options except for http.idleConnTimeout")
+		}
+		// http2.Transport dials "TLS" connection for http url when AllowHTTP is true, which is plain TCP here
+		return &http.Client{Transport: &thttpTransport{&http2.Transport{
+			AllowHTTP: true,
+			DialTLSContext: func(cxt context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+				return dial(cxt, network, addr)
+			},
+			IdleConnTimeout: idleConnTimeout,
+		}}}, nil
+	}
+
+	return &http.Client{Transport: &thttpTransport{&http.Transport{
+		Proxy:                 proxy,
+		DialContext:           dial,
+		TLSClientConfig:       tlsConfig,
+		TLSHandshakeTimeout:   connectTimeout,
+		ResponseHeaderTimeout: readTimeout,
+		MaxIdleConns:          maxIdleConns,
+		MaxIdleConnsPerHost:   opts.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       opts.MaxConnsPerHost,
+		IdleConnTimeout:       idleConnTimeout,
+		DisableKeepAlives:     opts.DisableKeepAlives,
+		ForceAttemptHTTP2:     opts.HTTP2,
+	}}}, nil
+}
+
+// thttpResponse is the HTTP response of a call recorded by [thttpTransport].
+// THttpClient doesn't expose the response, and it discards the response with status other than 200.
+type thttpResponse struct {
+	statusCode int
+	header     http.Header
+}
+
+type thttpResponseKey struct{}
+
+// withHTTPResponse returns context of a call, whose HTTP response is recorded to res.
+func withHTTPResponse(cxt context.Context, res *thttpResponse) context.Context {
+	return context.WithValue(cxt, thttpResponseKey{}, res)
+}
+
+// headers returns headers of the response, whose values of the same key are joined by comma.
+// This returns nil when no response is received.
+func (r *thttpResponse) headers() map[string]string {
+	if r.header == nil {
+		return nil
+	}
+	headers := make(map[string]string, len(r.header))
+	for k, v := range r.header {
+		headers[k] = strings.Join(v, ", ")
+	}
+	return headers
+}
+
+// thttpTransport is http.RoundTripper recording responses to [thttpResponse] in context of requests.
+type thttpTransport struct {
+	http.RoundTripper
+}
+
+var _ http.RoundTripper = (*thttpTransport)(nil)
+
+func (t *thttpTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.RoundTripper.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if res, ok := req.Context().Value(thttpResponseKey{}).(*thttpResponse); ok {
+		res.statusCode = resp.StatusCode
+		res.header = resp.Header
+	}
+	return resp, nil
+}
+
+// CloseIdleConnections closes idle connections of the wrapped transport, which is called by http.Client.
+func (t *thttpTransport) CloseIdleConnections() {
+	type closeIdler interface{ CloseIdleConnections() }
+	if tr, ok := t.RoundTripper.(closeIdler); ok {
+		tr.CloseIdleConnections()
+	}
+}
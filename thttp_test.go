@@ -0,0 +1,102 @@
+package thrift
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"golang.org/x/net/http2"
+)
+
+func TestNewHTTPClient_defaults(t *testing.T) {
+	// prepare
+	u, _ := url.Parse("http://127.0.0.1:8080/thrift")
+
+	// do
+	actual, err := newHTTPClient(u, &THTTPOptions{}, nil, nil, time.Second, 2*time.Second)
+	checkError(t, err)
+
+	// verify
+	transport := actual.Transport.(*thttpTransport).RoundTripper.(*http.Transport)
+	assert(t, "maxIdleConns", transport.MaxIdleConns, 100)
+	assert(t, "maxIdleConnsPerHost", transport.MaxIdleConnsPerHost, 0)
+	assert(t, "maxConnsPerHost", transport.MaxConnsPerHost, 0)
+	assert(t, "idleConnTimeout", transport.IdleConnTimeout, 90*time.Second)
+	assert(t, "disableKeepAlives", transport.DisableKeepAlives, false)
+	assert(t, "http2", transport.ForceAttemptHTTP2, false)
+	assert(t, "tlsHandshakeTimeout", transport.TLSHandshakeTimeout, time.Second)
+	assert(t, "responseHeaderTimeout", transport.ResponseHeaderTimeout, 2*time.Second)
+	assertTrue(t, "proxy", transport.Proxy != nil)
+}
+
+func TestNewHTTPClient_options(t *testing.T) {
+	// prepare
+	u, _ := url.Parse("https://127.0.0.1:8443/thrift")
+	opts := THTTPOptions{
+		MaxIdleConns:        10,
+		MaxIdleConnsPerHost: 5,
+		MaxConnsPerHost:     20,
+		IdleConnTimeout:     "30s",
+		DisableKeepAlives:   true,
+		HTTP2:               true,
+		Proxy:               "http://proxy.example.com:3128",
+	}
+
+	// do
+	actual, err := newHTTPClient(u, &opts, nil, nil, 0, 0)
+	checkError(t, err)
+
+	// verify
+	transport := actual.Transport.(*thttpTransport).RoundTripper.(*http.Transport)
+	assert(t, "maxIdleConns", transport.MaxIdleConns, 10)
+	assert(t, "maxIdleConnsPerHost", transport.MaxIdleConnsPerHost, 5)
+	assert(t, "maxConnsPerHost", transport.MaxConnsPerHost, 20)
+	assert(t, "idleConnTimeout", transport.IdleConnTimeout, 30*time.Second)
+	assert(t, "disableKeepAlives", transport.DisableKeepAlives, true)
+	assert(t, "http2", transport.ForceAttemptHTTP2, true)
+	req, _ := http.NewRequest(http.MethodPost, u.String(), nil)
+	proxy, err := transport.Proxy(req)
+	checkError(t, err)
+	assert(t, "proxy", proxy.String(), "http://proxy.example.com:3128")
+}
+
+func TestNewHTTPClient_h2c(t *testing.T) {
+	// prepare
+	u, _ := url.Parse("http://127.0.0.1:8080/thrift")
+
+	// do
+	actual, err := newHTTPClient(u, &THTTPOptions{H2C: true, IdleConnTimeout: "10s"}, nil, nil, 0, 0)
+	checkError(t, err)
+
+	// verify
+	transport := actual.Transport.(*thttpTransport).RoundTripper.(*http2.Transport)
+	assert(t, "allowHTTP", transport.AllowHTTP, true)
+	assert(t, "idleConnTimeout", transport.IdleConnTimeout, 10*time.Second)
+}
+
+func TestNewHTTPClient_invalid(t *testing.T) {
+	cases := map[string]struct {
+		url  string
+		opts THTTPOptions
+	}{
+		"invalid idle timeout": {"http://127.0.0.1:8080", THTTPOptions{IdleConnTimeout: "ten seconds"}},
+		"negative idle conns":  {"http://127.0.0.1:8080", THTTPOptions{MaxIdleConns: -1}},
+		"negative conns":       {"http://127.0.0.1:8080", THTTPOptions{MaxConnsPerHost: -1}},
+		"invalid proxy":        {"http://127.0.0.1:8080", THTTPOptions{Proxy: "proxy"}},
+		"h2c with https":       {"https://127.0.0.1:8080", THTTPOptions{H2C: true}},
+		"h2c with proxy":       {"http://127.0.0.1:8080", THTTPOptions{H2C: true, Proxy: "http://proxy.example.com:3128"}},
+		"h2c with pooling":     {"http://127.0.0.1:8080", THTTPOptions{H2C: true, MaxConnsPerHost: 1}},
+	}
+
+	for title, c := range cases {
+		// prepare
+		u, _ := url.Parse(c.url)
+
+		// do
+		_, err := newHTTPClient(u, &c.opts, nil, nil, 0, 0)
+
+		// verify
+		assertTrue(t, title, err != nil)
+	}
+}
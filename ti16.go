@@ -0,0 +1,56 @@
+package thrift
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+type TI16 struct {
+	value int16
+}
+
+func NewTI16(v int16) TI16 {
+	return TI16{value: v}
+}
+
+func (p TI16) Equals(other *TValue) bool {
+	o, ok := (*other).(TI16)
+	if !ok {
+		return false
+	}
+	return p.value == o.value
+}
+
+// See [Thrift IDL protocol spec]
+//
+//	<field> ::= <field-begin> <field-data> <field-end>
+//	<field-data> ::= I16
+//
+// [Thrift IDL protocol spec]: https://github.com/apache/thrift/blob/eec0b584e657e4250e22f3fd492858d632e2aa7b/doc/specs/thrift-protocol-spec.md
+func (p TI16) WriteFieldData(cxt context.Context, oprot thrift.TProtocol) (err error) {
+	if err = oprot.WriteI16(cxt, p.value); err != nil {
+		err = thrift.PrependError(fmt.Sprintf("%T.id (1) field write error: ", p), err)
+		return
+	}
+	return
+}
+
+func (p TI16) TType() thrift.TType {
+	return thrift.I16
+}
+
+func (p TI16) Export() any {
+	return p.value
+}
+
+func ReadI16(cxt context.Context, iprot thrift.TProtocol) (TValue, error) {
+	v, err := iprot.ReadI16(cxt)
+	if err != nil {
+		return nil, thrift.PrependError("error while reading i16: ", err)
+	}
+
+	res := NewTI16(v)
+	return res, nil
+}
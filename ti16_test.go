@@ -0,0 +1,106 @@
+package thrift
+
+import (
+	"context"
+	"testing"
+)
+
+func TestEquals_TI16_equals(t *testing.T) {
+	// prepare
+	a := NewTI16(1_000)
+	var b TValue = NewTI16(1_000)
+	expected := true
+
+	// do
+	actual := a.Equals(&b)
+
+	// verify
+	assert(t, "", actual, expected)
+}
+
+func TestEquals_TI16_not_equals(t *testing.T) {
+	// prepare
+	a := NewTI16(1_000)
+	var b TValue = NewTI16(1_001)
+	expected := false
+
+	// do
+	actual := a.Equals(&b)
+
+	// verify
+	assert(t, "", actual, expected)
+}
+
+func TestEquals_TI16_other_type(t *testing.T) {
+	// prepare
+	a := NewTI16(1_000)
+	var b TValue = NewTI32(1_000)
+	expected := false
+
+	// do
+	actual := a.Equals(&b)
+
+	// verify
+	assert(t, "", actual, expected)
+}
+
+func TestWriteFieldData_TI16(t *testing.T) {
+	// prepare
+	oprot := setupProtocol(t)
+	cxt := context.Background()
+	value := NewTI16(1_000)
+	var expected int16 = 1_000
+
+	// do
+	err := value.WriteFieldData(cxt, oprot)
+	checkError(t, err)
+
+	// verify
+	oprot.Flush(cxt)
+	actual, err := oprot.ReadI16(cxt)
+	checkError(t, err)
+	assert(t, "", actual, expected)
+}
+
+func TestReadI16(t *testing.T) {
+	// prepare
+	iprot := setupProtocol(t)
+	cxt := context.Background()
+	checkError(
+		t,
+		iprot.WriteI16(cxt, 1_000),
+	)
+	checkError(
+		t,
+		iprot.Flush(cxt),
+	)
+
+	// do
+	actual, err := ReadI16(cxt, iprot)
+	checkError(t, err)
+
+	// verify
+	a, ok := actual.(TI16)
+	assertTrue(t, "cast to TI16", ok)
+	assert(t, "result", a.value, 1_000)
+}
+
+func TestReadI16_InvalidType(t *testing.T) {
+	// prepare
+	iprot := setupProtocol(t)
+	cxt := context.Background()
+	checkError(
+		t,
+		iprot.WriteString(cxt, "foo"),
+	)
+	checkError(
+		t,
+		iprot.Flush(cxt),
+	)
+
+	// do
+	_, err := ReadI16(cxt, iprot)
+
+	// verify
+	assertTrue(t, "error expected", err != nil)
+}
@@ -0,0 +1,57 @@
+package thrift
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+// TI32 is Thrift `i32`. Enum is also i32 on the wire, which is [TEnum].
+type TI32 struct {
+	value int32
+}
+
+func NewTI32(v int32) TI32 {
+	return TI32{value: v}
+}
+
+func (p TI32) Equals(other *TValue) bool {
+	o, ok := (*other).(TI32)
+	if !ok {
+		return false
+	}
+	return p.value == o.value
+}
+
+// See [Thrift IDL protocol spec]
+//
+//	<field> ::= <field-begin> <field-data> <field-end>
+//	<field-data> ::= I32
+//
+// [Thrift IDL protocol spec]: https://github.com/apache/thrift/blob/eec0b584e657e4250e22f3fd492858d632e2aa7b/doc/specs/thrift-protocol-spec.md
+func (p TI32) WriteFieldData(cxt context.Context, oprot thrift.TProtocol) (err error) {
+	if err = oprot.WriteI32(cxt, p.value); err != nil {
+		err = thrift.PrependError(fmt.Sprintf("%T.id (1) field write error: ", p), err)
+		return
+	}
+	return
+}
+
+func (p TI32) TType() thrift.TType {
+	return thrift.I32
+}
+
+func (p TI32) Export() any {
+	return p.value
+}
+
+func ReadI32(cxt context.Context, iprot thrift.TProtocol) (TValue, error) {
+	v, err := iprot.ReadI32(cxt)
+	if err != nil {
+		return nil, thrift.PrependError("error while reading i32: ", err)
+	}
+
+	res := NewTI32(v)
+	return res, nil
+}
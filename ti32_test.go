@@ -0,0 +1,106 @@
+package thrift
+
+import (
+	"context"
+	"testing"
+)
+
+func TestEquals_TI32_equals(t *testing.T) {
+	// prepare
+	a := NewTI32(100_000)
+	var b TValue = NewTI32(100_000)
+	expected := true
+
+	// do
+	actual := a.Equals(&b)
+
+	// verify
+	assert(t, "", actual, expected)
+}
+
+func TestEquals_TI32_not_equals(t *testing.T) {
+	// prepare
+	a := NewTI32(100_000)
+	var b TValue = NewTI32(100_001)
+	expected := false
+
+	// do
+	actual := a.Equals(&b)
+
+	// verify
+	assert(t, "", actual, expected)
+}
+
+func TestEquals_TI32_other_type(t *testing.T) {
+	// prepare
+	a := NewTI32(100_000)
+	var b TValue = NewTEnum(100_000)
+	expected := false
+
+	// do
+	actual := a.Equals(&b)
+
+	// verify
+	assert(t, "", actual, expected)
+}
+
+func TestWriteFieldData_TI32(t *testing.T) {
+	// prepare
+	oprot := setupProtocol(t)
+	cxt := context.Background()
+	value := NewTI32(100_000)
+	var expected int32 = 100_000
+
+	// do
+	err := value.WriteFieldData(cxt, oprot)
+	checkError(t, err)
+
+	// verify
+	oprot.Flush(cxt)
+	actual, err := oprot.ReadI32(cxt)
+	checkError(t, err)
+	assert(t, "", actual, expected)
+}
+
+func TestReadI32(t *testing.T) {
+	// prepare
+	iprot := setupProtocol(t)
+	cxt := context.Background()
+	checkError(
+		t,
+		iprot.WriteI32(cxt, 100_000),
+	)
+	checkError(
+		t,
+		iprot.Flush(cxt),
+	)
+
+	// do
+	actual, err := ReadI32(cxt, iprot)
+	checkError(t, err)
+
+	// verify
+	a, ok := actual.(TI32)
+	assertTrue(t, "cast to TI32", ok)
+	assert(t, "result", a.value, 100_000)
+}
+
+func TestReadI32_InvalidType(t *testing.T) {
+	// prepare
+	iprot := setupProtocol(t)
+	cxt := context.Background()
+	checkError(
+		t,
+		iprot.WriteString(cxt, "foo"),
+	)
+	checkError(
+		t,
+		iprot.Flush(cxt),
+	)
+
+	// do
+	_, err := ReadI32(cxt, iprot)
+
+	// verify
+	assertTrue(t, "error expected", err != nil)
+}
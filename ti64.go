@@ -0,0 +1,59 @@
+package thrift
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+type TI64 struct {
+	value int64
+}
+
+func NewTI64(v int64) TI64 {
+	return TI64{value: v}
+}
+
+func (p TI64) Equals(other *TValue) bool {
+	o, ok := (*other).(TI64)
+	if !ok {
+		return false
+	}
+	return p.value == o.value
+}
+
+// See [Thrift IDL protocol spec]
+//
+//	<field> ::= <field-begin> <field-data> <field-end>
+//	<field-data> ::= I64
+//
+// [Thrift IDL protocol spec]: https://github.com/apache/thrift/blob/eec0b584e657e4250e22f3fd492858d632e2aa7b/doc/specs/thrift-protocol-spec.md
+func (p TI64) WriteFieldData(cxt context.Context, oprot thrift.TProtocol) (err error) {
+	if err = oprot.WriteI64(cxt, p.value); err != nil {
+		err = thrift.PrependError(fmt.Sprintf("%T.id (1) field write error: ", p), err)
+		return
+	}
+	return
+}
+
+func (p TI64) TType() thrift.TType {
+	return thrift.I64
+}
+
+// Export returns the value as *big.Int, which is BigInt in JavaScript,
+// since i64 can exceed the safe integer of number (2^53 - 1).
+func (p TI64) Export() any {
+	return big.NewInt(p.value)
+}
+
+func ReadI64(cxt context.Context, iprot thrift.TProtocol) (TValue, error) {
+	v, err := iprot.ReadI64(cxt)
+	if err != nil {
+		return nil, thrift.PrependError("error while reading i64: ", err)
+	}
+
+	res := NewTI64(v)
+	return res, nil
+}
@@ -0,0 +1,120 @@
+package thrift
+
+import (
+	"context"
+	"math/big"
+	"testing"
+)
+
+func TestEquals_TI64_equals(t *testing.T) {
+	// prepare
+	a := NewTI64(9_007_199_254_740_993)
+	var b TValue = NewTI64(9_007_199_254_740_993)
+	expected := true
+
+	// do
+	actual := a.Equals(&b)
+
+	// verify
+	assert(t, "", actual, expected)
+}
+
+func TestEquals_TI64_not_equals(t *testing.T) {
+	// prepare
+	a := NewTI64(9_007_199_254_740_993)
+	var b TValue = NewTI64(9_007_199_254_740_992)
+	expected := false
+
+	// do
+	actual := a.Equals(&b)
+
+	// verify
+	assert(t, "", actual, expected)
+}
+
+func TestEquals_TI64_other_type(t *testing.T) {
+	// prepare
+	a := NewTI64(9_007_199_254_740_993)
+	var b TValue = NewTI32(3)
+	expected := false
+
+	// do
+	actual := a.Equals(&b)
+
+	// verify
+	assert(t, "", actual, expected)
+}
+
+func TestWriteFieldData_TI64(t *testing.T) {
+	// prepare
+	oprot := setupProtocol(t)
+	cxt := context.Background()
+	value := NewTI64(9_007_199_254_740_993)
+	var expected int64 = 9_007_199_254_740_993
+
+	// do
+	err := value.WriteFieldData(cxt, oprot)
+	checkError(t, err)
+
+	// verify
+	oprot.Flush(cxt)
+	actual, err := oprot.ReadI64(cxt)
+	checkError(t, err)
+	assert(t, "", actual, expected)
+}
+
+func TestReadI64(t *testing.T) {
+	// prepare
+	iprot := setupProtocol(t)
+	cxt := context.Background()
+	checkError(
+		t,
+		iprot.WriteI64(cxt, 9_007_199_254_740_993),
+	)
+	checkError(
+		t,
+		iprot.Flush(cxt),
+	)
+
+	// do
+	actual, err := ReadI64(cxt, iprot)
+	checkError(t, err)
+
+	// verify
+	a, ok := actual.(TI64)
+	assertTrue(t, "cast to TI64", ok)
+	assert(t, "result", a.value, 9_007_199_254_740_993)
+}
+
+func TestReadI64_InvalidType(t *testing.T) {
+	// prepare
+	iprot := setupProtocol(t)
+	cxt := context.Background()
+	checkError(
+		t,
+		iprot.WriteString(cxt, "foo"),
+	)
+	checkError(
+		t,
+		iprot.Flush(cxt),
+	)
+
+	// do
+	_, err := ReadI64(cxt, iprot)
+
+	// verify
+	assertTrue(t, "error expected", err != nil)
+}
+
+func TestExport_TI64(t *testing.T) {
+	// prepare
+	value := NewTI64(9_007_199_254_740_993)
+
+	// do
+	actual := value.Export()
+
+	// verify
+	i, ok := actual.(*big.Int)
+	assertTrue(t, "cast to *big.Int", ok)
+	assert(t, "", i.String(), "9007199254740993")
+}
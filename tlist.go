@@ -70,7 +70,21 @@ func (p *TList) TType() thrift.TType {
 	return thrift.LIST
 }
 
+// Export returns an array of exported elements.
+func (p *TList) Export() any {
+	res := make([]any, 0, len(p.value))
+	for _, v := range p.value {
+		res = append(res, export(v))
+	}
+	return res
+}
+
 func ReadList(cxt context.Context, iprot thrift.TProtocol) (TValue, error) {
+	return readList(cxt, iprot, nil)
+}
+
+// readList reads list whose elements are decoded by elemHint.
+func readList(cxt context.Context, iprot thrift.TProtocol, elemHint *TTypeHint) (TValue, error) {
 	valueType, size, err := iprot.ReadListBegin(cxt)
 	if err != nil {
 		return nil, thrift.PrependError("error while reading list begin", err)
@@ -79,13 +93,17 @@ func ReadList(cxt context.Context, iprot thrift.TProtocol) (TValue, error) {
 	var tlist []TValue
 	for i := 0; i < size; i++ {
 		var tv TValue
-		tv, err = ReadContainerData(valueType, cxt, iprot)
+		tv, err = ReadContainerDataWithHint(valueType, elemHint, cxt, iprot)
 		if err != nil {
 			return nil, thrift.PrependError("error reading list field", err)
 		}
 		tlist = append(tlist, tv)
 	}
 
+	if err = iprot.ReadListEnd(cxt); err != nil {
+		return nil, thrift.PrependError("error while reading list end", err)
+	}
+
 	res := NewTList(&tlist, valueType)
 	return res, nil
 }
@@ -3,6 +3,7 @@ package thrift
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/apache/thrift/lib/go/thrift"
 )
@@ -26,8 +27,12 @@ func (p *TMap) Equals(other *TValue) bool {
 		return false
 	}
 	for pk, pv := range p.value {
-		ov := o.value[pk]
-		if !pv.Equals(&ov) {
+		ov, ok := o.value[pk]
+		if !ok {
+			// keys equal to each other can be different Go values, such as enum with and without the name
+			ov, ok = lookup(o.value, pk)
+		}
+		if !ok || !pv.Equals(&ov) {
 			return false
 		}
 	}
@@ -74,7 +79,49 @@ func (p *TMap) TType() thrift.TType {
 	return thrift.MAP
 }
 
+// Export returns an object whose keys are exported keys formatted as string, since keys of JavaScript
+// object are string.
+func (p *TMap) Export() any {
+	res := make(map[string]any, len(p.value))
+	for k, v := range p.value {
+		if b, ok := k.(TBinary); ok {
+			// object keys are strings, so binary keys are hex strings
+			res[b.Hex()] = export(v)
+			continue
+		}
+		if e, ok := k.(TEnum); ok {
+			// named enums export as objects, so enum keys are names, or values when names are unknown
+			if e.name != "" {
+				res[e.name] = export(v)
+			} else {
+				res[strconv.Itoa(int(e.value))] = export(v)
+			}
+			continue
+		}
+		res[fmt.Sprint(export(k))] = export(v)
+	}
+	return res
+}
+
+// lookup returns a value of a key equal to k in m.
+func lookup(m map[TValue]TValue, k TValue) (TValue, bool) {
+	if k == nil {
+		return nil, false
+	}
+	for mk, mv := range m {
+		if k.Equals(&mk) {
+			return mv, true
+		}
+	}
+	return nil, false
+}
+
 func ReadMap(cxt context.Context, iproto thrift.TProtocol) (TValue, error) {
+	return readMap(cxt, iproto, nil, nil)
+}
+
+// readMap reads map whose keys and values are decoded by keyHint and valueHint.
+func readMap(cxt context.Context, iproto thrift.TProtocol, keyHint, valueHint *TTypeHint) (TValue, error) {
 	keyType, valueType, size, err := iproto.ReadMapBegin(cxt)
 	if err != nil {
 		return nil, thrift.PrependError("error while reading map field: ", err)
@@ -82,22 +129,28 @@ func ReadMap(cxt context.Context, iproto thrift.TProtocol) (TValue, error) {
 
 	tmap := make(map[TValue]TValue)
 	for i := 0; i < size; i++ {
-		if err = readFeidlDataList(cxt, iproto, &tmap, keyType, valueType); err != nil {
+		if err = readFeidlDataList(cxt, iproto, &tmap, keyType, valueType, keyHint, valueHint); err != nil {
 			return nil, thrift.PrependError("error while reading map: ", err)
 		}
 	}
 
+	if err = iproto.ReadMapEnd(cxt); err != nil {
+		return nil, thrift.PrependError("error while reading map end: ", err)
+	}
+
 	res := NewTMap(keyType, valueType, &tmap)
 	return res, nil
 }
 
-func readFeidlDataList(cxt context.Context, iprot thrift.TProtocol, tmap *map[TValue]TValue, ktype, vtype thrift.TType) error {
+func readFeidlDataList(
+	cxt context.Context, iprot thrift.TProtocol, tmap *map[TValue]TValue, ktype, vtype thrift.TType, khint, vhint *TTypeHint,
+) error {
 	var key, value TValue
 	var err error
-	if key, err = ReadContainerData(ktype, cxt, iprot); err != nil {
+	if key, err = ReadContainerDataWithHint(ktype, khint, cxt, iprot); err != nil {
 		return err
 	}
-	if value, err = ReadContainerData(vtype, cxt, iprot); err != nil {
+	if value, err = ReadContainerDataWithHint(vtype, vhint, cxt, iprot); err != nil {
 		return err
 	}
 
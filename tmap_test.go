@@ -352,3 +352,34 @@ func TestReadBool_Invalid_type(t *testing.T) {
 	// verfiy
 	assertTrue(t, "", err != nil)
 }
+
+func TestEquals_EnumKeyWithName(t *testing.T) {
+	// prepare
+	a := NewTMap(thrift.I32, thrift.BOOL, &map[TValue]TValue{NewTEnumWithName(1, "ONE"): NewTBool(true)})
+	var b TValue = NewTMap(thrift.I32, thrift.BOOL, &map[TValue]TValue{NewTEnum(1): NewTBool(true)})
+	expected := true
+
+	// do
+	actual := a.Equals(&b)
+
+	// verify
+	assert(t, "", actual, expected)
+}
+
+func TestExport_EnumKey(t *testing.T) {
+	// prepare
+	m := NewTMap(thrift.I32, thrift.BOOL, &map[TValue]TValue{
+		NewTEnumWithName(1, "ONE"): NewTBool(true),
+		NewTEnumWithName(2, ""):    NewTBool(false),
+		NewTEnum(3):                NewTBool(true),
+	})
+
+	// do
+	actual := m.Export().(map[string]any)
+
+	// verify
+	assert(t, "len", len(actual), 3)
+	assert(t, "named", actual["ONE"].(bool), true)
+	assert(t, "unknown name", actual["2"].(bool), false)
+	assert(t, "unnamed", actual["3"].(bool), true)
+}
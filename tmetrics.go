@@ -0,0 +1,168 @@
+package thrift
+
+import (
+	"strconv"
+	"time"
+
+	"go.k6.io/k6/metrics"
+)
+
+// Names of metrics emitted for each call.
+const (
+	metricReqs        = "thrift_reqs"
+	metricReqDuration = "thrift_req_duration"
+	metricReqFailed   = "thrift_req_failed"
+	metricReqSize     = "thrift_req_size"
+	metricRespSize    = "thrift_resp_size"
+
+	// phases of a call, which mirror http_req_* metrics
+	metricReqConnecting     = "thrift_req_connecting"
+	metricReqTLSHandshaking = "thrift_req_tls_handshaking"
+	metricReqSending        = "thrift_req_sending"
+	metricReqWaiting        = "thrift_req_waiting"
+	metricReqReceiving      = "thrift_req_receiving"
+	metricReqDecoding       = "thrift_req_decoding"
+)
+
+// Names of tags of metrics.
+const (
+	tagMethod   = "method"
+	tagService  = "service"
+	tagEndpoint = "endpoint"
+	tagProtocol = "protocol"
+	tagStatus   = "status"
+	// tagErrorCode is added to failed calls with [TCallResult.ErrorCode].
+	tagErrorCode = "error_code"
+	// tagHTTPStatus is added to calls over HTTP with [TCallResult.Status]. HTTP status is not tagged as `status`,
+	// which is already `ok` or the error kind for all transports.
+	tagHTTPStatus = "http_status"
+)
+
+// statusOK is `status` tag of successful calls. It is [TCallResult.ErrorKind] for failed calls.
+const statusOK = "ok"
+
+// tmetrics is k6 metrics emitted by clients.
+type tmetrics struct {
+	reqs        *metrics.Metric
+	reqDuration *metrics.Metric
+	reqFailed   *metrics.Metric
+	reqSize     *metrics.Metric
+	respSize    *metrics.Metric
+
+	reqConnecting     *metrics.Metric
+	reqTLSHandshaking *metrics.Metric
+	reqSending        *metrics.Metric
+	reqWaiting        *metrics.Metric
+	reqReceiving      *metrics.Metric
+	reqDecoding       *metrics.Metric
+}
+
+// registerMetrics registers metrics to registry. Metrics already registered by other VUs are reused.
+func registerMetrics(registry *metrics.Registry) (*tmetrics, error) {
+	var err error
+	newMetric := func(name string, typ metrics.MetricType, t ...metrics.ValueType) *metrics.Metric {
+		if err != nil {
+			return nil
+		}
+		var m *metrics.Metric
+		m, err = registry.NewMetric(name, typ, t...)
+		return m
+	}
+
+	m := &tmetrics{
+		reqs:        newMetric(metricReqs, metrics.Counter),
+		reqDuration: newMetric(metricReqDuration, metrics.Trend, metrics.Time),
+		reqFailed:   newMetric(metricReqFailed, metrics.Rate),
+		reqSize:     newMetric(metricReqSize, metrics.Trend, metrics.Data),
+		respSize:    newMetric(metricRespSize, metrics.Trend, metrics.Data),
+
+		reqConnecting:     newMetric(metricReqConnecting, metrics.Trend, metrics.Time),
+		reqTLSHandshaking: newMetric(metricReqTLSHandshaking, metrics.Trend, metrics.Time),
+		reqSending:        newMetric(metricReqSending, metrics.Trend, metrics.Time),
+		reqWaiting:        newMetric(metricReqWaiting, metrics.Trend, metrics.Time),
+		reqReceiving:      newMetric(metricReqReceiving, metrics.Trend, metrics.Time),
+		reqDecoding:       newMetric(metricReqDecoding, metrics.Trend, metrics.Time),
+	}
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// pushMetrics emits metrics of a call to method of service, which ended with result.
+// Phases after sending are not emitted for oneway call. Bytes of the call are also added to k6's built-in data_sent and data_received
+// unless k6 counts them by its Dialer.
+// Nothing is emitted out of VU context such as init context.
+func (c *TClient) pushMetrics(method, service string, oneway bool, result *TCallResult) {
+	if c.metrics == nil || c.vu == nil || c.vu.State() == nil {
+		return
+	}
+	state := c.vu.State()
+	now := time.Now()
+
+	status := statusOK
+	failed := 0.0
+	if !result.IsSuccess() {
+		status = result.ErrorKind()
+		failed = 1
+	}
+	ctm := state.Tags.GetCurrentValues()
+	tags := ctm.Tags.
+		With(tagMethod, method).
+		With(tagService, service).
+		With(tagEndpoint, c.url.String()).
+		With(tagProtocol, c.protocol).
+		With(tagStatus, status)
+	if result.errCode != "" {
+		tags = tags.With(tagErrorCode, result.errCode)
+	}
+	if result.statusCode != 0 {
+		tags = tags.With(tagHTTPStatus, strconv.Itoa(result.statusCode))
+	}
+
+	sample := func(metric *metrics.Metric, value float64) metrics.Sample {
+		return metrics.Sample{
+			TimeSeries: metrics.TimeSeries{Metric: metric, Tags: tags},
+			Time:       now,
+			Metadata:   ctm.Metadata,
+			Value:      value,
+		}
+	}
+	samples := []metrics.Sample{
+		sample(c.metrics.reqs, 1),
+		sample(c.metrics.reqDuration, metrics.D(result.duration)),
+		sample(c.metrics.reqFailed, failed),
+	}
+	// no bytes for calls failed before sending, and no response for oneway calls.
+	// Bytes of connections dialed by Dialer of k6 are added to data_sent and data_received by k6 itself.
+	if result.reqSize > 0 {
+		samples = append(samples, sample(c.metrics.reqSize, float64(result.reqSize)))
+		if state.Dialer == nil {
+			samples = append(samples, sample(state.BuiltinMetrics.DataSent, float64(result.reqSize)))
+		}
+	}
+	if result.respSize > 0 {
+		samples = append(samples, sample(c.metrics.respSize, float64(result.respSize)))
+		if state.Dialer == nil {
+			samples = append(samples, sample(state.BuiltinMetrics.DataReceived, float64(result.respSize)))
+		}
+	}
+	if t := result.timings; t != nil {
+		samples = append(samples,
+			sample(c.metrics.reqConnecting, metrics.D(t.connecting)),
+			sample(c.metrics.reqTLSHandshaking, metrics.D(t.tlsHandshaking)),
+			sample(c.metrics.reqSending, metrics.D(t.sending)))
+		if !oneway {
+			samples = append(samples,
+				sample(c.metrics.reqWaiting, metrics.D(t.waiting)),
+				sample(c.metrics.reqReceiving, metrics.D(t.receiving)),
+				sample(c.metrics.reqDecoding, metrics.D(t.decoding)))
+		}
+	}
+
+	metrics.PushIfNotDone(c.vu.Context(), state.Samples, metrics.ConnectedSamples{
+		Samples: samples,
+		Tags:    tags,
+		Time:    now,
+	})
+}
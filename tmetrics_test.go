@@ -0,0 +1,64 @@
+package thrift
+
+import (
+	"testing"
+
+	"go.k6.io/k6/js/modulestest"
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/metrics"
+)
+
+// setupVUContext moves rt to VU context, and returns a channel receiving metrics pushed in the VU.
+func setupVUContext(rt *modulestest.Runtime) chan metrics.SampleContainer {
+	samples := make(chan metrics.SampleContainer, 100)
+	registry := rt.VU.InitEnvField.Registry
+	rt.MoveToVUContext(&lib.State{
+		Samples:        samples,
+		Tags:           lib.NewVUStateTags(registry.RootTagSet()),
+		BuiltinMetrics: rt.BuiltinMetrics,
+	})
+	return samples
+}
+
+func TestPushMetrics(t *testing.T) {
+	// prepare
+	rt, m := setupModule(t)
+	client, err := m.NewClient(TClientOptions{URL: "tcp://127.0.0.1:1", Transport: "socket", Service: "UserService"})
+	checkError(t, err)
+	samples := setupVUContext(rt)
+
+	// do
+	client.Call("getUser", nil, nil)
+
+	// verify
+	container := <-samples
+	actual := map[string]metrics.Sample{}
+	for _, s := range container.GetSamples() {
+		actual[s.Metric.Name] = s
+	}
+	assert(t, "samples", len(actual), 3)
+	assert(t, "reqs", actual["thrift_reqs"].Value, 1.0)
+	assert(t, "failed", actual["thrift_req_failed"].Value, 1.0)
+	assertTrue(t, "duration", actual["thrift_req_duration"].Value > 0)
+	tags := actual["thrift_reqs"].Tags.Map()
+	assert(t, "method", tags["method"], "getUser")
+	assert(t, "service", tags["service"], "UserService")
+	assert(t, "endpoint", tags["endpoint"], "tcp://127.0.0.1:1")
+	assert(t, "protocol", tags["protocol"], "binary")
+	assert(t, "status", tags["status"], "transport")
+	assert(t, "error_code", tags["error_code"], "NOT_OPEN")
+}
+
+func TestPushMetrics_initContext(t *testing.T) {
+	// prepare
+	_, m := setupModule(t)
+	client, err := m.NewClient(TClientOptions{URL: "tcp://127.0.0.1:1", Transport: "socket"})
+	checkError(t, err)
+
+	// do
+	result := client.Call("getUser", nil, nil)
+
+	// verify
+	// nothing is pushed without VU state, which blocks otherwise
+	assertTrue(t, "failure", !result.IsSuccess())
+}
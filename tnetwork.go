@@ -0,0 +1,50 @@
+package thrift
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+
+	"go.k6.io/k6/lib"
+)
+
+// dialer returns Dialer of the VU, which obeys networking options of k6 such as `hosts`, `blacklistIPs`,
+// `blockHostnames`, `localIPs` and `dns`. net.Dialer is returned out of VU context.
+// Bytes of connections dialed by Dialer of the VU are added to `data_sent` and `data_received` by k6.
+func (c *TClient) dialer() lib.DialContexter {
+	if c.vu != nil && c.vu.State() != nil && c.vu.State().Dialer != nil {
+		return c.vu.State().Dialer
+	}
+	return &net.Dialer{}
+}
+
+// dialContext dials addr for "http" transport by [TClient.dialer] within connectTimeout.
+func (c *TClient) dialContext(cxt context.Context, network, addr string) (net.Conn, error) {
+	if c.connectTimeout > 0 {
+		var cancel context.CancelFunc
+		cxt, cancel = context.WithTimeout(cxt, c.connectTimeout)
+		defer cancel()
+	}
+	return c.dialer().DialContext(cxt, network, addr)
+}
+
+// applyVUOptions applies TLS options of k6 such as `insecureSkipTLSVerify` and `tlsAuth` to the client at the first
+// call in VU context, since they are not available in init context where the client is created.
+// http.Client is recreated with them, and the previous one is used only by connections opened before that.
+func (c *TClient) applyVUOptions() {
+	if c.vu == nil || c.vu.State() == nil || c.vu.State().TLSConfig == nil {
+		return
+	}
+	c.vuOnce.Do(func() {
+		tlsConfig := mergeTLSConfig(c.tlsConfig, c.vu.State().TLSConfig)
+		httpClient, err := newHTTPClient(c.url, &c.httpOpts, tlsConfig, c.dialContext, c.connectTimeout, c.readTimeout)
+		if err != nil {
+			// options are validated when the client is created
+			slog.Error(fmt.Sprintf("ERROR while applying k6 options: %v", err))
+			return
+		}
+		c.tlsConfig = tlsConfig
+		c.httpClient = httpClient
+	})
+}
@@ -7,14 +7,23 @@ import (
 	"github.com/apache/thrift/lib/go/thrift"
 )
 
+// TResponse is a result struct of a method, whose field 0 is the return value and
+// the others are exceptions declared in `throws` of the method.
 type TResponse struct {
 	values map[int16]TValue
+	// hint is a hint of the return value, which can be nil.
+	hint *TTypeHint
 }
 
 func NewTResponse() *TResponse {
 	return &TResponse{values: make(map[int16]TValue)}
 }
 
+// NewTResponseWithHint creates [TResponse] whose return value is decoded by hint.
+func NewTResponseWithHint(hint *TTypeHint) *TResponse {
+	return &TResponse{values: make(map[int16]TValue), hint: hint}
+}
+
 func (p TResponse) Values() *map[int16]TValue {
 	return &p.values
 }
@@ -36,22 +45,25 @@ func (p *TResponse) Read(cxt context.Context, iprot thrift.TProtocol) error {
 			break
 		}
 
-		switch fieldId {
-		case 0:
-			var v TValue
-			v, err = ReadContainerData(fieldTypeId, cxt, iprot)
-			if err != nil {
-				return thrift.PrependError(fmt.Sprintf("%T read field (%d, %v) error: ", p, fieldId, fieldTypeId), err)
-			}
-
-			p.values[fieldId] = v
-		default:
-			err = iprot.Skip(cxt, fieldTypeId)
+		var hint *TTypeHint
+		if fieldId == 0 {
+			hint = p.hint
 		}
-
+		var v TValue
+		v, err = ReadContainerDataWithHint(fieldTypeId, hint, cxt, iprot)
 		if err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T read field (%d, %v) error: ", p, fieldId, fieldTypeId), err)
 		}
+
+		p.values[fieldId] = v
+
+		if err = iprot.ReadFieldEnd(cxt); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T read field end (%d, %v) error: ", p, fieldId, fieldTypeId), err)
+		}
+	}
+
+	if err := iprot.ReadStructEnd(cxt); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
 	}
 
 	return nil
@@ -68,6 +80,21 @@ func (p *TResponse) Write(cxt context.Context, oprot thrift.TProtocol) error {
 	return nil
 }
 
+// exception returns the declared exception in the result, or nil when there is no exception.
+func (p *TResponse) exception() *TException {
+	var id int16
+	for k, v := range p.values {
+		// only one of them is set by server, but the smallest ID is taken for consistency
+		if k > 0 && v != nil && (id == 0 || k < id) {
+			id = k
+		}
+	}
+	if id == 0 {
+		return nil
+	}
+	return newDeclaredException(id, p.values[id])
+}
+
 func (p *TResponse) Add(key int16, value TValue) {
 	p.values[key] = value
 }
@@ -0,0 +1,47 @@
+package thrift
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+func TestTResponse_Read_exception(t *testing.T) {
+	// prepare
+	buf := thrift.NewTMemoryBuffer()
+	prot := thrift.NewTBinaryProtocolConf(buf, nil)
+	notFound := NewTStruct(&map[TStructField]TValue{*NewTStructField(1, ""): NewTstring("not found")})
+	checkError(t, prot.WriteStructBegin(context.Background(), "result"))
+	checkError(t, prot.WriteFieldBegin(context.Background(), "notFound", thrift.STRUCT, 2))
+	checkError(t, notFound.WriteFieldData(context.Background(), prot))
+	checkError(t, prot.WriteFieldEnd(context.Background()))
+	checkError(t, prot.WriteFieldStop(context.Background()))
+	checkError(t, prot.WriteStructEnd(context.Background()))
+	res := NewTResponse()
+
+	// do
+	err := res.Read(context.Background(), prot)
+	checkError(t, err)
+
+	// verify
+	assertTrue(t, "no body", res.values[0] == nil)
+	actual := res.exception()
+	assertTrue(t, "exception", actual != nil)
+	assert(t, "kind", actual.Kind, "declared")
+	assert(t, "id", actual.ID, int16(2))
+	expected := TValue(notFound)
+	assertTrue(t, "value", actual.Value.Equals(&expected))
+}
+
+func TestTResponse_exception_none(t *testing.T) {
+	// prepare
+	res := NewTResponse()
+	res.Add(0, NewTstring("body"))
+
+	// do
+	actual := res.exception()
+
+	// verify
+	assertTrue(t, "no exception", actual == nil)
+}
@@ -0,0 +1,133 @@
+package thrift
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+// TSet is Thrift `set`. Elements are held in order of insertion, and compared regardless of the order.
+type TSet struct {
+	value     []TValue
+	valueType thrift.TType
+}
+
+func NewTSet(v *[]TValue, valueType thrift.TType) *TSet {
+	return &TSet{value: *v, valueType: valueType}
+}
+
+// Equals returns true when other has the same elements in any order.
+func (p *TSet) Equals(other *TValue) bool {
+	o, ok := (*other).(*TSet)
+	if !ok {
+		return false
+	}
+	if len(p.value) != len(o.value) {
+		return false
+	}
+	matched := make([]bool, len(o.value))
+	for _, pv := range p.value {
+		found := false
+		for i, ov := range o.value {
+			if !matched[i] && pv.Equals(&ov) {
+				matched[i] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// duplicated returns indexes of the first pair of equal elements, or -1 when all elements are unique.
+func (p *TSet) duplicated() (int, int) {
+	for i, v := range p.value {
+		for j := i + 1; j < len(p.value); j++ {
+			if v.Equals(&p.value[j]) {
+				return i, j
+			}
+		}
+	}
+	return -1, -1
+}
+
+// See [Thrift IDL protocol spec]
+//
+//	<field>          ::= <field-begin> <set> <field-end>
+//	<set>            ::= <set-begin> <field-data>* <set-end>
+//	<set-begin>      ::= <set-elem-type> <set-size>
+//	<set-elem-type>  ::= <field-type>
+//	<set-size>       ::= I32
+//	<field-data>     ::= I8 | I16 | I32 | I64 | DOUBLE | STRING | BINARY
+//			<struct> | <map> | <list> | <set>
+//
+// [Thrift IDL protocol spec]: https://github.com/apache/thrift/blob/eec0b584e657e4250e22f3fd492858d632e2aa7b/doc/specs/thrift-protocol-spec.md
+func (p *TSet) WriteFieldData(cxt context.Context, oprot thrift.TProtocol) (err error) {
+	if i, j := p.duplicated(); i >= 0 {
+		return thrift.NewTProtocolExceptionWithType(
+			thrift.INVALID_DATA, fmt.Errorf("%T elements %d and %d are duplicated", p, i, j))
+	}
+	if err = oprot.WriteSetBegin(cxt, p.valueType, len(p.value)); err != nil {
+		err = thrift.PrependError(fmt.Sprintf("%T write set begin error", p), err)
+		return
+	}
+
+	for _, v := range p.value {
+		if err = v.WriteFieldData(cxt, oprot); err != nil {
+			err = thrift.PrependError(fmt.Sprintf("%T write set field data error", p), err)
+			return
+		}
+	}
+
+	if err = oprot.WriteSetEnd(cxt); err != nil {
+		err = thrift.PrependError(fmt.Sprintf("%T write set end error", p), err)
+		return
+	}
+	return
+}
+
+func (p *TSet) TType() thrift.TType {
+	return thrift.SET
+}
+
+// Export returns an array of exported elements.
+func (p *TSet) Export() any {
+	res := make([]any, 0, len(p.value))
+	for _, v := range p.value {
+		res = append(res, export(v))
+	}
+	return res
+}
+
+func ReadSet(cxt context.Context, iprot thrift.TProtocol) (TValue, error) {
+	return readSet(cxt, iprot, nil)
+}
+
+// readSet reads set whose elements are decoded by elemHint.
+func readSet(cxt context.Context, iprot thrift.TProtocol, elemHint *TTypeHint) (TValue, error) {
+	valueType, size, err := iprot.ReadSetBegin(cxt)
+	if err != nil {
+		return nil, thrift.PrependError("error while reading set begin", err)
+	}
+
+	tset := make([]TValue, 0, size)
+	for i := 0; i < size; i++ {
+		var tv TValue
+		tv, err = ReadContainerDataWithHint(valueType, elemHint, cxt, iprot)
+		if err != nil {
+			return nil, thrift.PrependError("error reading set field", err)
+		}
+		tset = append(tset, tv)
+	}
+
+	if err = iprot.ReadSetEnd(cxt); err != nil {
+		return nil, thrift.PrependError("error while reading set end", err)
+	}
+
+	res := NewTSet(&tset, valueType)
+	return res, nil
+}
@@ -0,0 +1,134 @@
+package thrift
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+func TestEquals_TSet_equals(t *testing.T) {
+	// prepare
+	a := NewTSet(&[]TValue{NewTstring("a"), NewTstring("b")}, thrift.STRING)
+	var b TValue = NewTSet(&[]TValue{NewTstring("a"), NewTstring("b")}, thrift.STRING)
+	expected := true
+
+	// do
+	actual := a.Equals(&b)
+
+	// verify
+	assert(t, "", actual, expected)
+}
+
+func TestEquals_TSet_differentOrder(t *testing.T) {
+	// prepare
+	a := NewTSet(&[]TValue{NewTstring("a"), NewTstring("b")}, thrift.STRING)
+	var b TValue = NewTSet(&[]TValue{NewTstring("b"), NewTstring("a")}, thrift.STRING)
+	expected := true
+
+	// do
+	actual := a.Equals(&b)
+
+	// verify
+	assert(t, "", actual, expected)
+}
+
+func TestEquals_TSet_differentValue(t *testing.T) {
+	// prepare
+	a := NewTSet(&[]TValue{NewTstring("a"), NewTstring("b")}, thrift.STRING)
+	var b TValue = NewTSet(&[]TValue{NewTstring("a"), NewTstring("c")}, thrift.STRING)
+	expected := false
+
+	// do
+	actual := a.Equals(&b)
+
+	// verify
+	assert(t, "", actual, expected)
+}
+
+func TestEquals_TSet_differentCount(t *testing.T) {
+	// prepare
+	a := NewTSet(&[]TValue{NewTstring("a"), NewTstring("b")}, thrift.STRING)
+	var b TValue = NewTSet(&[]TValue{NewTstring("a")}, thrift.STRING)
+	expected := false
+
+	// do
+	actual := a.Equals(&b)
+
+	// verify
+	assert(t, "", actual, expected)
+}
+
+func TestEquals_TSet_otherType(t *testing.T) {
+	// prepare
+	a := NewTSet(&[]TValue{NewTstring("a")}, thrift.STRING)
+	var b TValue = NewTList(&[]TValue{NewTstring("a")}, thrift.STRING)
+	expected := false
+
+	// do
+	actual := a.Equals(&b)
+
+	// verify
+	assert(t, "", actual, expected)
+}
+
+func TestWriteFieldData_TSet(t *testing.T) {
+	// prepare
+	oprot := setupProtocol(t)
+	cxt := context.Background()
+	value := NewTSet(&[]TValue{NewTI32(1), NewTI32(2)}, thrift.I32)
+
+	// do
+	err := value.WriteFieldData(cxt, oprot)
+	checkError(t, err)
+
+	// verify
+	oprot.Flush(cxt)
+	elemType, size, err := oprot.ReadSetBegin(cxt)
+	checkError(t, err)
+	assert(t, "elemType", elemType, thrift.I32)
+	assert(t, "size", size, 2)
+	for _, expected := range []int32{1, 2} {
+		actual, err := oprot.ReadI32(cxt)
+		checkError(t, err)
+		assert(t, "element", actual, expected)
+	}
+}
+
+func TestWriteFieldData_TSet_duplicated(t *testing.T) {
+	// prepare
+	oprot := setupProtocol(t)
+	cxt := context.Background()
+	value := NewTSet(&[]TValue{
+		NewTStruct(&map[TStructField]TValue{*NewTStructField(1, ""): NewTstring("a")}),
+		NewTStruct(&map[TStructField]TValue{*NewTStructField(1, ""): NewTstring("a")}),
+	}, thrift.STRUCT)
+
+	// do
+	err := value.WriteFieldData(cxt, oprot)
+
+	// verify
+	assertTrue(t, "error expected", err != nil)
+	kind, code := classifyError(cxt, err, 0)
+	assert(t, "kind", kind, errorKindProtocol)
+	assert(t, "code", code, "INVALID_DATA")
+}
+
+func TestReadSet(t *testing.T) {
+	// prepare
+	iprot := setupProtocol(t)
+	cxt := context.Background()
+	var expected TValue = NewTSet(&[]TValue{NewTstring("a"), NewTstring("b")}, thrift.STRING)
+	checkError(t, expected.WriteFieldData(cxt, iprot))
+	checkError(t, iprot.Flush(cxt))
+
+	// do
+	actual, err := ReadContainerData(thrift.SET, cxt, iprot)
+	checkError(t, err)
+
+	// verify
+	a, ok := actual.(*TSet)
+	assertTrue(t, "cast to TSet", ok)
+	assert(t, "valueType", a.valueType, thrift.STRING)
+	assertTrue(t, "equals", a.Equals(&expected))
+}
@@ -0,0 +1,186 @@
+package thrift
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"go.k6.io/k6/lib"
+)
+
+// tsocket is a TCP socket transport used by socket transports instead of thrift.TSocket and thrift.TSSLSocket.
+// Unlike thrift.TSocket, this has separate read and write timeouts, and blocking IO can be
+// interrupted when the context of a call is done.
+//
+// thrift.TSocket can't be used even with a connection dialed by k6 (thrift.NewTSocketFromConnConf), since it sets
+// a deadline of the connection before every read and write, which overrides the past deadline set by interrupt.
+// It also has only one timeout for reads and writes. TLS handshake is done here instead of thrift.TSSLSocket
+// to use the dialer of k6 and measure the handshake separately.
+type tsocket struct {
+	addr string
+	// dialer dials addr. net.Dialer is used when nil.
+	dialer lib.DialContexter
+	// tlsConfig is nil when TLS is not used.
+	tlsConfig *tls.Config
+
+	connectTimeout time.Duration
+	readTimeout    time.Duration
+	writeTimeout   time.Duration
+
+	conn net.Conn
+	// connecting is time spent in connecting by OpenContext.
+	connecting time.Duration
+	// tlsHandshaking is time spent in TLS handshake by OpenContext.
+	tlsHandshaking time.Duration
+
+	mu sync.Mutex
+	// interrupted is true after interrupt, and the socket can't be used anymore.
+	interrupted bool
+}
+
+var _ thrift.TTransport = (*tsocket)(nil)
+
+// errInterrupted is returned by IO of the socket after interrupt.
+var errInterrupted = thrift.NewTTransportException(thrift.TIMED_OUT, "socket is interrupted")
+
+// Open opens the socket without context. Use OpenContext instead.
+func (s *tsocket) Open() error {
+	return s.OpenContext(context.Background())
+}
+
+// OpenContext connects to the address, and completes TLS handshake when TLS is used.
+// Both of them are canceled when cxt is done or connectTimeout has passed.
+func (s *tsocket) OpenContext(cxt context.Context) error {
+	if s.IsOpen() {
+		return thrift.NewTTransportException(thrift.ALREADY_OPEN, "socket is already open")
+	}
+	if s.connectTimeout > 0 {
+		var cancel context.CancelFunc
+		cxt, cancel = context.WithTimeout(cxt, s.connectTimeout)
+		defer cancel()
+	}
+
+	var dialer lib.DialContexter = &net.Dialer{}
+	if s.dialer != nil {
+		dialer = s.dialer
+	}
+	start := time.Now()
+	conn, err := dialer.DialContext(cxt, "tcp", s.addr)
+	s.connecting = time.Since(start)
+	if err != nil {
+		if isTimeout(err) {
+			return thrift.NewTTransportExceptionFromError(err)
+		}
+		// the same as thrift.TSocket
+		return thrift.NewTTransportException(thrift.NOT_OPEN, err.Error())
+	}
+	if s.tlsConfig != nil {
+		cfg := s.tlsConfig
+		if cfg.ServerName == "" {
+			cfg = cfg.Clone()
+			cfg.ServerName, _, _ = net.SplitHostPort(s.addr)
+		}
+		tlsConn := tls.Client(conn, cfg)
+		start = time.Now()
+		err = tlsConn.HandshakeContext(cxt)
+		s.tlsHandshaking = time.Since(start)
+		if err != nil {
+			conn.Close()
+			return thrift.NewTTransportExceptionFromError(err)
+		}
+		conn = tlsConn
+	}
+	s.conn = conn
+	return nil
+}
+
+func (s *tsocket) IsOpen() bool {
+	return s.conn != nil
+}
+
+// Close closes the socket. This can be called while interrupt is running.
+func (s *tsocket) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+func (s *tsocket) Read(p []byte) (int, error) {
+	if !s.IsOpen() {
+		return 0, thrift.NewTTransportException(thrift.NOT_OPEN, "socket is not open")
+	}
+	if err := s.setDeadline(s.conn.SetReadDeadline, s.readTimeout); err != nil {
+		return 0, err
+	}
+	n, err := s.conn.Read(p)
+	if err != nil {
+		return n, thrift.NewTTransportExceptionFromError(err)
+	}
+	return n, nil
+}
+
+func (s *tsocket) Write(p []byte) (int, error) {
+	if !s.IsOpen() {
+		return 0, thrift.NewTTransportException(thrift.NOT_OPEN, "socket is not open")
+	}
+	if err := s.setDeadline(s.conn.SetWriteDeadline, s.writeTimeout); err != nil {
+		return 0, err
+	}
+	n, err := s.conn.Write(p)
+	if err != nil {
+		return n, thrift.NewTTransportExceptionFromError(err)
+	}
+	return n, nil
+}
+
+func (s *tsocket) Flush(context.Context) error {
+	return nil
+}
+
+func (s *tsocket) RemainingBytes() uint64 {
+	// the same as thrift.TSocket, which doesn't know the size
+	return ^uint64(0)
+}
+
+// interrupt makes blocking IO of the socket return immediately. The socket must be closed after that.
+func (s *tsocket) interrupt() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.interrupted = true
+	if s.conn != nil {
+		_ = s.conn.SetDeadline(time.Unix(1, 0))
+	}
+}
+
+// isInterrupted reports whether interrupt has been called.
+func (s *tsocket) isInterrupted() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.interrupted
+}
+
+// setDeadline sets deadline with timeout from now by set, or clears it when timeout is 0.
+// This fails after interrupt not to override the deadline set by it.
+func (s *tsocket) setDeadline(set func(time.Time) error, timeout time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.interrupted {
+		return errInterrupted
+	}
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+	return set(deadline)
+}
@@ -0,0 +1,96 @@
+package thrift
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+func openSocket(t *testing.T, sock *tsocket) {
+	checkError(t, sock.OpenContext(context.Background()))
+	t.Cleanup(func() { sock.Close() })
+}
+
+func TestTSocket_readTimeout(t *testing.T) {
+	// prepare
+	url, _ := startListener(t)
+	sock := &tsocket{addr: strings.TrimPrefix(url, "tcp://"), readTimeout: 50 * time.Millisecond}
+	openSocket(t, sock)
+
+	// do
+	_, err := sock.Read(make([]byte, 1))
+
+	// verify
+	var transErr thrift.TTransportException
+	assertTrue(t, "transport error", errors.As(err, &transErr))
+	assert(t, "type", transErr.TypeId(), thrift.TIMED_OUT)
+}
+
+func TestTSocket_interrupt(t *testing.T) {
+	// prepare
+	url, _ := startListener(t)
+	sock := &tsocket{addr: strings.TrimPrefix(url, "tcp://")}
+	openSocket(t, sock)
+	time.AfterFunc(50*time.Millisecond, sock.interrupt)
+
+	// do
+	_, err := sock.Read(make([]byte, 1))
+
+	// verify
+	assertTrue(t, "error", err != nil)
+	assertTrue(t, "interrupted", sock.isInterrupted())
+	_, err = sock.Write([]byte{1})
+	assertTrue(t, "write after interrupt", errors.Is(err, errInterrupted))
+}
+
+func TestTSocket_closeWhileInterrupting(t *testing.T) {
+	// prepare
+	url, _ := startListener(t)
+	sock := &tsocket{addr: strings.TrimPrefix(url, "tcp://")}
+	openSocket(t, sock)
+	done := make(chan struct{})
+
+	// do
+	go func() {
+		defer close(done)
+		sock.interrupt()
+	}()
+	err := sock.Close()
+	<-done
+
+	// verify
+	checkError(t, err)
+	assertTrue(t, "closed", !sock.IsOpen())
+	assertTrue(t, "interrupted", sock.isInterrupted())
+}
+
+func TestTSocket_openCanceled(t *testing.T) {
+	// prepare
+	sock := &tsocket{addr: "127.0.0.1:1"}
+	cxt, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// do
+	err := sock.OpenContext(cxt)
+
+	// verify
+	assertTrue(t, "error", err != nil)
+	assertTrue(t, "not open", !sock.IsOpen())
+}
+
+func TestTSocket_notOpen(t *testing.T) {
+	// prepare
+	sock := &tsocket{addr: "127.0.0.1:1"}
+
+	// do
+	_, err := sock.Read(make([]byte, 1))
+
+	// verify
+	var transErr thrift.TTransportException
+	assertTrue(t, "transport error", errors.As(err, &transErr))
+	assert(t, "type", transErr.TypeId(), thrift.NOT_OPEN)
+}
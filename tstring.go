@@ -41,6 +41,10 @@ func (p TString) TType() thrift.TType {
 	return thrift.STRING
 }
 
+func (p TString) Export() any {
+	return p.value
+}
+
 func ReadString(cxt context.Context, iproto thrift.TProtocol) (TValue, error) {
 	v, err := iproto.ReadString(cxt)
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"maps"
 	"slices"
+	"strconv"
 
 	"github.com/apache/thrift/lib/go/thrift"
 )
@@ -96,7 +97,21 @@ func (p *TStruct) TType() thrift.TType {
 	return thrift.STRUCT
 }
 
+// Export returns an object whose keys are field IDs, since field names are not sent by most protocols.
+func (p *TStruct) Export() any {
+	res := make(map[string]any, len(p.value))
+	for f, v := range p.value {
+		res[strconv.Itoa(int(f.id))] = export(v)
+	}
+	return res
+}
+
 func ReadStruct(cxt context.Context, iprot thrift.TProtocol) (TValue, error) {
+	return readStruct(cxt, iprot, nil)
+}
+
+// readStruct reads struct whose fields are decoded by hints of fields in hint.
+func readStruct(cxt context.Context, iprot thrift.TProtocol, hint *TTypeHint) (TValue, error) {
 	fieldName, err := iprot.ReadStructBegin(cxt)
 	if err != nil {
 		return nil, thrift.PrependError(fmt.Sprintf("error while struct begin (%s)", fieldName), err)
@@ -113,7 +128,7 @@ func ReadStruct(cxt context.Context, iprot thrift.TProtocol) (TValue, error) {
 		}
 
 		var tv TValue
-		tv, err = ReadContainerData(ftype, cxt, iprot)
+		tv, err = ReadContainerDataWithHint(ftype, hint.field(fid), cxt, iprot)
 		if err != nil {
 			return nil, err
 		}
@@ -346,6 +346,30 @@ func TestReadStruct_StringToBool(t *testing.T) {
 	}
 }
 
+func TestReadStruct_Numbers(t *testing.T) {
+	// prepare
+	iprot := setupProtocol(t)
+	cxt := context.Background()
+	// field names are not sent by JSON protocol
+	var expected TValue = NewTStruct(
+		&map[TStructField]TValue{
+			*NewTStructField(1, ""): NewTByte(-1),
+			*NewTStructField(2, ""): NewTI16(1_000),
+			*NewTStructField(3, ""): NewTI64(9_007_199_254_740_993),
+			*NewTStructField(4, ""): NewTDouble(0.5),
+		},
+	)
+	checkError(t, expected.WriteFieldData(cxt, iprot))
+	checkError(t, iprot.Flush(cxt))
+
+	// do
+	actual, err := ReadStruct(cxt, iprot)
+	checkError(t, err)
+
+	// verify
+	assertTrue(t, "equals", actual.Equals(&expected))
+}
+
 func TestReadStruct_ContainerData(t *testing.T) {
 	t.Skip("FIXME: Somehow this test fails. will be re-enabled.")
 
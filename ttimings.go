@@ -0,0 +1,87 @@
+package thrift
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// ttimings is time spent in each phase of a call, which mirrors http_req_* metrics of k6.
+type ttimings struct {
+	// connecting is time spent in connecting to the server. This is 0 when a connection is reused.
+	connecting time.Duration
+	// tlsHandshaking is time spent in TLS handshake. This is 0 when a connection is reused.
+	tlsHandshaking time.Duration
+	// sending is time spent in writing the request.
+	sending time.Duration
+	// waiting is time spent in waiting for the first bytes of the response.
+	waiting time.Duration
+	// receiving is time spent in reading the rest of the response.
+	receiving time.Duration
+	// decoding is time spent in decoding the response except for reading.
+	decoding time.Duration
+}
+
+// setReceived sets waiting, receiving and decoding of the response read by a call from sent to received,
+// with time spent in reading counted by counter.
+func (t *ttimings) setReceived(sent, received time.Time, counter *tcounter) {
+	t.waiting = counter.firstReading
+	t.receiving = counter.reading - counter.firstReading
+	t.decoding = max(received.Sub(sent)-counter.reading, 0)
+}
+
+// thttpTrace records time of phases of HTTP request sent by THttpClient.
+type thttpTrace struct {
+	// hooks are called by other goroutines of http.Transport
+	mu sync.Mutex
+
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	gotConn      time.Time
+	wroteRequest time.Time
+	firstByte    time.Time
+}
+
+func (tr *thttpTrace) clientTrace() *httptrace.ClientTrace {
+	record := func(at *time.Time) {
+		tr.mu.Lock()
+		defer tr.mu.Unlock()
+		*at = time.Now()
+	}
+	return &httptrace.ClientTrace{
+		ConnectStart:         func(string, string) { record(&tr.connectStart) },
+		ConnectDone:          func(string, string, error) { record(&tr.connectDone) },
+		TLSHandshakeStart:    func() { record(&tr.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { record(&tr.tlsDone) },
+		GotConn:              func(httptrace.GotConnInfo) { record(&tr.gotConn) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { record(&tr.wroteRequest) },
+		GotFirstResponseByte: func() { record(&tr.firstByte) },
+	}
+}
+
+// apply overrides phases of t until the first byte of the response, which are recorded by HTTP client.
+// Reading the response body is not traced, and it is measured by [ttimings.setReceived].
+func (tr *thttpTrace) apply(t *ttimings) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	t.connecting = between(tr.connectStart, tr.connectDone)
+	t.tlsHandshaking = between(tr.tlsStart, tr.tlsDone)
+	if !tr.wroteRequest.IsZero() {
+		t.sending = between(tr.gotConn, tr.wroteRequest)
+	}
+	// the first read of body doesn't wait, since headers of the response have been received
+	t.receiving += t.waiting
+	t.waiting = between(tr.wroteRequest, tr.firstByte)
+}
+
+// between returns duration from start to end, or 0 when either of them is not recorded.
+func between(start, end time.Time) time.Duration {
+	if start.IsZero() || end.IsZero() {
+		return 0
+	}
+	return max(end.Sub(start), 0)
+}
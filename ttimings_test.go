@@ -0,0 +1,61 @@
+package thrift
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTTimings_setReceived(t *testing.T) {
+	// prepare
+	sent := time.Now()
+	counter := &tcounter{firstReading: 30 * time.Millisecond, reading: 50 * time.Millisecond}
+	actual := &ttimings{}
+
+	// do
+	actual.setReceived(sent, sent.Add(60*time.Millisecond), counter)
+
+	// verify
+	assert(t, "waiting", actual.waiting, 30*time.Millisecond)
+	assert(t, "receiving", actual.receiving, 20*time.Millisecond)
+	assert(t, "decoding", actual.decoding, 10*time.Millisecond)
+}
+
+func TestTHTTPTrace_apply(t *testing.T) {
+	// prepare
+	start := time.Now()
+	at := func(ms int) time.Time { return start.Add(time.Duration(ms) * time.Millisecond) }
+	trace := &thttpTrace{
+		connectStart: at(0),
+		connectDone:  at(10),
+		tlsStart:     at(10),
+		tlsDone:      at(30),
+		gotConn:      at(30),
+		wroteRequest: at(35),
+		firstByte:    at(85),
+	}
+	actual := &ttimings{sending: 100 * time.Millisecond, waiting: time.Millisecond, receiving: 2 * time.Millisecond}
+
+	// do
+	trace.apply(actual)
+
+	// verify
+	assert(t, "connecting", actual.connecting, 10*time.Millisecond)
+	assert(t, "tlsHandshaking", actual.tlsHandshaking, 20*time.Millisecond)
+	assert(t, "sending", actual.sending, 5*time.Millisecond)
+	assert(t, "waiting", actual.waiting, 50*time.Millisecond)
+	assert(t, "receiving", actual.receiving, 3*time.Millisecond)
+}
+
+func TestTHTTPTrace_applyReusedConnection(t *testing.T) {
+	// prepare
+	start := time.Now()
+	trace := &thttpTrace{gotConn: start, wroteRequest: start.Add(time.Millisecond), firstByte: start.Add(2 * time.Millisecond)}
+	actual := &ttimings{}
+
+	// do
+	trace.apply(actual)
+
+	// verify
+	assert(t, "connecting", actual.connecting, 0)
+	assert(t, "tlsHandshaking", actual.tlsHandshaking, 0)
+}
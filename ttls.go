@@ -0,0 +1,112 @@
+package thrift
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+)
+
+// tlsVersions is TLS versions which can be given as `tls.minVersion` option.
+var tlsVersions = map[string]uint16{
+	"tls1.0": tls.VersionTLS10,
+	"tls1.1": tls.VersionTLS11,
+	"tls1.2": tls.VersionTLS12,
+	"tls1.3": tls.VersionTLS13,
+}
+
+// TTLSOptions is TLS options of [TClientOptions].
+// TLS is used when url scheme is `https` for "http" transport, or `tcps` for socket transports.
+//
+//	tls: {
+//	  ca: open("./ca.pem"),
+//	  cert: open("./client.pem"),
+//	  key: open("./client-key.pem"),
+//	  serverName: "thrift.example.com",
+//	  minVersion: "tls1.2",
+//	  cipherSuites: ["TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"],
+//	  insecureSkipVerify: false,
+//	}
+type TTLSOptions struct {
+	// CA is PEM encoded CA certificates to verify server certificate. System CA is used when empty.
+	CA string `js:"ca"`
+	// Cert is PEM encoded client certificate for mutual TLS. Key is required with it.
+	Cert string `js:"cert"`
+	// Key is PEM encoded private key of Cert.
+	Key string `js:"key"`
+	// ServerName overrides server name used for SNI and certificate verification.
+	ServerName string `js:"serverName"`
+	// MinVersion is a minimum TLS version, "tls1.0", "tls1.1", "tls1.2" or "tls1.3". Default is "tls1.2".
+	MinVersion string `js:"minVersion"`
+	// CipherSuites is names of cipher suites such as "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256".
+	// Go's default is used when empty. This has no effect on TLS 1.3.
+	CipherSuites []string `js:"cipherSuites"`
+	// InsecureSkipVerify skips server certificate verification.
+	InsecureSkipVerify bool `js:"insecureSkipVerify"`
+}
+
+func newTLSConfig(opts *TTLSOptions) (*tls.Config, error) {
+	cfg := &tls.Config{
+		ServerName:         opts.ServerName,
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: opts.InsecureSkipVerify,
+	}
+
+	if opts.CA != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(opts.CA)) {
+			return nil, fmt.Errorf("no valid PEM certificate found in tls.ca")
+		}
+		cfg.RootCAs = pool
+	}
+
+	if opts.Cert != "" || opts.Key != "" {
+		cert, err := tls.X509KeyPair([]byte(opts.Cert), []byte(opts.Key))
+		if err != nil {
+			return nil, fmt.Errorf("invalid tls.cert or tls.key: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	if opts.MinVersion != "" {
+		v, ok := tlsVersions[opts.MinVersion]
+		if !ok {
+			return nil, fmt.Errorf("unsupported tls.minVersion %q", opts.MinVersion)
+		}
+		cfg.MinVersion = v
+	}
+
+	for _, name := range opts.CipherSuites {
+		id, err := cipherSuiteID(name)
+		if err != nil {
+			return nil, err
+		}
+		cfg.CipherSuites = append(cfg.CipherSuites, id)
+	}
+
+	return cfg, nil
+}
+
+func cipherSuiteID(name string) (uint16, error) {
+	for _, s := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		if s.Name == name {
+			return s.ID, nil
+		}
+	}
+	return 0, fmt.Errorf("unsupported tls.cipherSuites %q", name)
+}
+
+// mergeTLSConfig returns own with TLS options of k6 given by k6Config. Options of the client take precedence,
+// but `insecureSkipTLSVerify` of k6 skips verification as well, and client certificates of `tlsAuth` are used
+// unless the client has its own certificate.
+func mergeTLSConfig(own, k6Config *tls.Config) *tls.Config {
+	cfg := own.Clone()
+	cfg.InsecureSkipVerify = cfg.InsecureSkipVerify || k6Config.InsecureSkipVerify
+	if len(cfg.Certificates) == 0 && cfg.GetClientCertificate == nil {
+		cfg.Certificates = k6Config.Certificates
+		cfg.GetClientCertificate = k6Config.GetClientCertificate
+	}
+	if cfg.KeyLogWriter == nil {
+		cfg.KeyLogWriter = k6Config.KeyLogWriter
+	}
+	return cfg
+}
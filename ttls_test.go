@@ -0,0 +1,85 @@
+package thrift
+
+import (
+	"crypto/tls"
+	"testing"
+)
+
+func TestNewTLSConfig_defaults(t *testing.T) {
+	// prepare
+	opts := TTLSOptions{}
+
+	// do
+	actual, err := newTLSConfig(&opts)
+	checkError(t, err)
+
+	// verify
+	assert(t, "minVersion", actual.MinVersion, tls.VersionTLS12)
+	assert(t, "insecureSkipVerify", actual.InsecureSkipVerify, false)
+	assertTrue(t, "rootCAs", actual.RootCAs == nil)
+	assert(t, "certificates", len(actual.Certificates), 0)
+	assert(t, "cipherSuites", len(actual.CipherSuites), 0)
+}
+
+func TestNewTLSConfig_options(t *testing.T) {
+	// prepare
+	opts := TTLSOptions{
+		ServerName:         "thrift.example.com",
+		MinVersion:         "tls1.3",
+		CipherSuites:       []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_RSA_WITH_AES_128_CBC_SHA"},
+		InsecureSkipVerify: true,
+	}
+
+	// do
+	actual, err := newTLSConfig(&opts)
+	checkError(t, err)
+
+	// verify
+	assert(t, "serverName", actual.ServerName, "thrift.example.com")
+	assert(t, "minVersion", actual.MinVersion, tls.VersionTLS13)
+	assert(t, "insecureSkipVerify", actual.InsecureSkipVerify, true)
+	assert(t, "cipherSuites size", len(actual.CipherSuites), 2)
+	assert(t, "cipherSuites[0]", actual.CipherSuites[0], tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256)
+	assert(t, "cipherSuites[1]", actual.CipherSuites[1], tls.TLS_RSA_WITH_AES_128_CBC_SHA)
+}
+
+func TestNewTLSConfig_invalid(t *testing.T) {
+	cases := map[string]TTLSOptions{
+		"invalid ca":          {CA: "not a PEM"},
+		"cert without key":    {Cert: "not a PEM"},
+		"key without cert":    {Key: "not a PEM"},
+		"unknown min version": {MinVersion: "ssl3"},
+		"unknown cipher":      {CipherSuites: []string{"TLS_UNKNOWN"}},
+	}
+
+	for title, opts := range cases {
+		// do
+		_, err := newTLSConfig(&opts)
+
+		// verify
+		assertTrue(t, title, err != nil)
+	}
+}
+
+func TestMergeTLSConfig(t *testing.T) {
+	// prepare
+	cert := tls.Certificate{Certificate: [][]byte{[]byte("cert")}}
+	k6Config := &tls.Config{
+		InsecureSkipVerify: true,
+		Certificates:       []tls.Certificate{cert},
+		MinVersion:         tls.VersionTLS10,
+	}
+	own, err := newTLSConfig(&TTLSOptions{ServerName: "thrift.example.com"})
+	checkError(t, err)
+
+	// do
+	actual := mergeTLSConfig(own, k6Config)
+
+	// verify
+	assert(t, "insecureSkipVerify", actual.InsecureSkipVerify, true)
+	assert(t, "certificates", len(actual.Certificates), 1)
+	assert(t, "serverName", actual.ServerName, "thrift.example.com")
+	// the client's option takes precedence
+	assert(t, "minVersion", actual.MinVersion, tls.VersionTLS12)
+	assert(t, "own", own.InsecureSkipVerify, false)
+}
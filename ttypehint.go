@@ -0,0 +1,142 @@
+package thrift
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+// Types of [TTypeHint], which are the same as types of Thrift IDL.
+const (
+	hintBool   = "bool"
+	hintByte   = "byte"
+	hintI8     = "i8"
+	hintI16    = "i16"
+	hintI32    = "i32"
+	hintI64    = "i64"
+	hintDouble = "double"
+	hintString = "string"
+	hintBinary = "binary"
+	hintUUID   = "uuid"
+	hintEnum   = "enum"
+	hintList   = "list"
+	hintSet    = "set"
+	hintMap    = "map"
+	hintStruct = "struct"
+)
+
+// hintTTypes is thrift.TType on the wire of each type of [TTypeHint].
+var hintTTypes = map[string]thrift.TType{
+	hintBool:   thrift.BOOL,
+	hintByte:   thrift.BYTE,
+	hintI8:     thrift.I08,
+	hintI16:    thrift.I16,
+	hintI32:    thrift.I32,
+	hintI64:    thrift.I64,
+	hintDouble: thrift.DOUBLE,
+	hintString: thrift.STRING,
+	hintBinary: thrift.STRING,
+	hintUUID:   thrift.UUID,
+	hintEnum:   thrift.I32,
+	hintList:   thrift.LIST,
+	hintSet:    thrift.SET,
+	hintMap:    thrift.MAP,
+	hintStruct: thrift.STRUCT,
+}
+
+// TTypeHint is a type of a value in the response, which tells types sharing the same type on the wire,
+// such as i32 and enum, or string and binary. Values without hints are decoded by types on the wire.
+//
+//	{
+//	  type: "struct",
+//	  fields: {
+//	    1: { type: "enum", enum: { 1: "ONE", 2: "TWO" } },
+//	    2: { type: "list", elem: { type: "binary" } },
+//	    3: { type: "map", key: { type: "string" }, value: { type: "i32" } },
+//	  },
+//	}
+type TTypeHint struct {
+	// Type is a type in Thrift IDL, such as "i32", "enum", "binary" or "struct".
+	Type string `js:"type"`
+	// Enum is names of values of "enum". Value without name is decoded without name.
+	Enum map[int32]string `js:"enum"`
+	// Elem is a hint of elements of "list" or "set".
+	Elem *TTypeHint `js:"elem"`
+	// Key is a hint of keys of "map".
+	Key *TTypeHint `js:"key"`
+	// Value is a hint of values of "map".
+	Value *TTypeHint `js:"value"`
+	// Fields is hints of fields of "struct" by field IDs. Fields without hints are decoded by types on the wire.
+	Fields map[int16]*TTypeHint `js:"fields"`
+}
+
+// validate returns an error when h or hints in h have an unknown type. path is a name of h in the error.
+func (h *TTypeHint) validate(path string) error {
+	if h == nil {
+		return nil
+	}
+	if _, ok := hintTTypes[h.Type]; !ok {
+		return fmt.Errorf("unsupported type %q of %s", h.Type, path)
+	}
+	if len(h.Enum) > 0 && h.Type != hintEnum {
+		return fmt.Errorf("enum of %s can be used only with enum type, but was %s", path, h.Type)
+	}
+	if err := h.Elem.validate(path + ".elem"); err != nil {
+		return err
+	}
+	if err := h.Key.validate(path + ".key"); err != nil {
+		return err
+	}
+	if err := h.Value.validate(path + ".value"); err != nil {
+		return err
+	}
+	for _, id := range slices.Sorted(maps.Keys(h.Fields)) {
+		if err := h.Fields[id].validate(fmt.Sprintf("%s.fields.%d", path, id)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// of returns h when h is a hint of a value of ttype on the wire, or nil otherwise.
+func (h *TTypeHint) of(ttype thrift.TType) *TTypeHint {
+	if h == nil || hintTTypes[h.Type] != ttype {
+		return nil
+	}
+	return h
+}
+
+// is returns true when h is non-nil and of type typ.
+func (h *TTypeHint) is(typ string) bool {
+	return h != nil && h.Type == typ
+}
+
+func (h *TTypeHint) elem() *TTypeHint {
+	if h == nil {
+		return nil
+	}
+	return h.Elem
+}
+
+func (h *TTypeHint) key() *TTypeHint {
+	if h == nil {
+		return nil
+	}
+	return h.Key
+}
+
+func (h *TTypeHint) value() *TTypeHint {
+	if h == nil {
+		return nil
+	}
+	return h.Value
+}
+
+func (h *TTypeHint) field(id int16) *TTypeHint {
+	if h == nil {
+		return nil
+	}
+	return h.Fields[id]
+}
@@ -0,0 +1,94 @@
+package thrift
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+func TestTTypeHint_validate(t *testing.T) {
+	cases := map[string]struct {
+		hint  *TTypeHint
+		valid bool
+	}{
+		"nil":    {nil, true},
+		"scalar": {&TTypeHint{Type: "i32"}, true},
+		"nested": {
+			&TTypeHint{Type: "struct", Fields: map[int16]*TTypeHint{
+				1: {Type: "map", Key: &TTypeHint{Type: "binary"}, Value: &TTypeHint{Type: "enum", Enum: map[int32]string{1: "ONE"}}},
+				2: {Type: "set", Elem: &TTypeHint{Type: "uuid"}},
+			}},
+			true,
+		},
+		"unknown type":  {&TTypeHint{Type: "integer"}, false},
+		"empty type":    {&TTypeHint{}, false},
+		"enum of i32":   {&TTypeHint{Type: "i32", Enum: map[int32]string{1: "ONE"}}, false},
+		"unknown elem":  {&TTypeHint{Type: "list", Elem: &TTypeHint{Type: "integer"}}, false},
+		"unknown key":   {&TTypeHint{Type: "map", Key: &TTypeHint{Type: "integer"}}, false},
+		"unknown value": {&TTypeHint{Type: "map", Value: &TTypeHint{Type: "integer"}}, false},
+		"unknown field": {&TTypeHint{Type: "struct", Fields: map[int16]*TTypeHint{1: {Type: "integer"}}}, false},
+	}
+
+	for title, c := range cases {
+		t.Run(title, func(t *testing.T) {
+			// do
+			err := c.hint.validate("response")
+
+			// verify
+			assert(t, "valid", err == nil, c.valid)
+		})
+	}
+}
+
+func TestReadContainerDataWithHint(t *testing.T) {
+	cases := map[string]struct {
+		value    TValue
+		hint     *TTypeHint
+		expected TValue
+	}{
+		"i32 without hint": {NewTI32(1), nil, NewTI32(1)},
+		"enum":             {NewTI32(1), &TTypeHint{Type: "enum"}, NewTEnum(1)},
+		"enum with names":  {NewTI32(1), &TTypeHint{Type: "enum", Enum: map[int32]string{1: "ONE"}}, NewTEnumWithName(1, "ONE")},
+		"binary":           {NewTBinary([]byte("foo")), &TTypeHint{Type: "binary"}, NewTBinary([]byte("foo"))},
+		"mismatched":       {NewTI32(1), &TTypeHint{Type: "string"}, NewTI32(1)},
+		"list": {
+			NewTList(&[]TValue{NewTI32(1)}, thrift.I32),
+			&TTypeHint{Type: "list", Elem: &TTypeHint{Type: "enum"}},
+			NewTList(&[]TValue{NewTEnum(1)}, thrift.I32),
+		},
+		"set": {
+			NewTSet(&[]TValue{NewTI32(1)}, thrift.I32),
+			&TTypeHint{Type: "set", Elem: &TTypeHint{Type: "enum"}},
+			NewTSet(&[]TValue{NewTEnum(1)}, thrift.I32),
+		},
+		"map": {
+			NewTMap(thrift.I32, thrift.STRING, &map[TValue]TValue{NewTI32(1): NewTBinary([]byte("foo"))}),
+			&TTypeHint{Type: "map", Key: &TTypeHint{Type: "enum"}, Value: &TTypeHint{Type: "binary"}},
+			NewTMap(thrift.I32, thrift.STRING, &map[TValue]TValue{NewTEnum(1): NewTBinary([]byte("foo"))}),
+		},
+		"struct": {
+			NewTStruct(&map[TStructField]TValue{*NewTStructField(1, ""): NewTI32(1), *NewTStructField(2, ""): NewTI32(2)}),
+			&TTypeHint{Type: "struct", Fields: map[int16]*TTypeHint{1: {Type: "enum"}}},
+			NewTStruct(&map[TStructField]TValue{*NewTStructField(1, ""): NewTEnum(1), *NewTStructField(2, ""): NewTI32(2)}),
+		},
+	}
+
+	for title, c := range cases {
+		t.Run(title, func(t *testing.T) {
+			// prepare
+			iprot := setupProtocol(t)
+			cxt := context.Background()
+			checkError(t, c.value.WriteFieldData(cxt, iprot))
+			checkError(t, iprot.Flush(cxt))
+
+			// do
+			actual, err := ReadContainerDataWithHint(c.value.TType(), c.hint, cxt, iprot)
+			checkError(t, err)
+
+			// verify
+			assertTrue(t, "equals", actual.Equals(&c.expected))
+			assertTrue(t, "type", c.expected.Equals(&actual))
+		})
+	}
+}
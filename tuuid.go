@@ -0,0 +1,58 @@
+package thrift
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+// TUUID is Thrift `uuid`, which is 16 bytes on the wire.
+type TUUID struct {
+	value thrift.Tuuid
+}
+
+func NewTUUID(v thrift.Tuuid) TUUID {
+	return TUUID{value: v}
+}
+
+func (p TUUID) Equals(other *TValue) bool {
+	o, ok := (*other).(TUUID)
+	if !ok {
+		return false
+	}
+	return p.value == o.value
+}
+
+// See [Thrift IDL protocol spec]
+//
+//	<field> ::= <field-begin> <field-data> <field-end>
+//	<field-data> ::= UUID
+//
+// [Thrift IDL protocol spec]: https://github.com/apache/thrift/blob/eec0b584e657e4250e22f3fd492858d632e2aa7b/doc/specs/thrift-protocol-spec.md
+func (p TUUID) WriteFieldData(cxt context.Context, oprot thrift.TProtocol) (err error) {
+	if err = oprot.WriteUUID(cxt, p.value); err != nil {
+		err = thrift.PrependError(fmt.Sprintf("%T.id (1) field write error: ", p), err)
+		return
+	}
+	return
+}
+
+func (p TUUID) TType() thrift.TType {
+	return thrift.UUID
+}
+
+// Export returns the UUID in canonical form such as "6ba7b810-9dad-11d1-80b4-00c04fd430c8".
+func (p TUUID) Export() any {
+	return p.value.String()
+}
+
+func ReadUUID(cxt context.Context, iprot thrift.TProtocol) (TValue, error) {
+	v, err := iprot.ReadUUID(cxt)
+	if err != nil {
+		return nil, thrift.PrependError("error while reading uuid: ", err)
+	}
+
+	res := NewTUUID(v)
+	return res, nil
+}
@@ -0,0 +1,96 @@
+package thrift
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+const testUUID = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+
+func TestEquals_TUUID_equals(t *testing.T) {
+	// prepare
+	a := NewTUUID(thrift.Must(thrift.ParseTuuid(testUUID)))
+	var b TValue = NewTUUID(thrift.Must(thrift.ParseTuuid(testUUID)))
+	expected := true
+
+	// do
+	actual := a.Equals(&b)
+
+	// verify
+	assert(t, "", actual, expected)
+}
+
+func TestEquals_TUUID_other_type(t *testing.T) {
+	// prepare
+	a := NewTUUID(thrift.Must(thrift.ParseTuuid(testUUID)))
+	var b TValue = NewTstring(testUUID)
+	expected := false
+
+	// do
+	actual := a.Equals(&b)
+
+	// verify
+	assert(t, "", actual, expected)
+}
+
+func TestWriteFieldData_TUUID(t *testing.T) {
+	// prepare
+	oprot := setupProtocol(t)
+	cxt := context.Background()
+	value := NewTUUID(thrift.Must(thrift.ParseTuuid(testUUID)))
+
+	// do
+	err := value.WriteFieldData(cxt, oprot)
+	checkError(t, err)
+
+	// verify
+	oprot.Flush(cxt)
+	actual, err := oprot.ReadUUID(cxt)
+	checkError(t, err)
+	assert(t, "", actual.String(), testUUID)
+}
+
+func TestReadUUID(t *testing.T) {
+	// prepare
+	iprot := setupProtocol(t)
+	cxt := context.Background()
+	checkError(
+		t,
+		iprot.WriteUUID(cxt, thrift.Must(thrift.ParseTuuid(testUUID))),
+	)
+	checkError(
+		t,
+		iprot.Flush(cxt),
+	)
+
+	// do
+	actual, err := ReadUUID(cxt, iprot)
+	checkError(t, err)
+
+	// verify
+	a, ok := actual.(TUUID)
+	assertTrue(t, "cast to TUUID", ok)
+	assert(t, "result", a.Export().(string), testUUID)
+}
+
+func TestReadUUID_InvalidType(t *testing.T) {
+	// prepare
+	iprot := setupProtocol(t)
+	cxt := context.Background()
+	checkError(
+		t,
+		iprot.WriteString(cxt, "foo"),
+	)
+	checkError(
+		t,
+		iprot.Flush(cxt),
+	)
+
+	// do
+	_, err := ReadUUID(cxt, iprot)
+
+	// verify
+	assertTrue(t, "error expected", err != nil)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/grafana/sobek"
 )
 
 type TValue interface {
@@ -19,4 +20,36 @@ type TValue interface {
 	WriteFieldData(cxt context.Context, oprot thrift.TProtocol) error
 	// TType returns type in Thrift.
 	TType() thrift.TType
+	// Export returns the value as a plain Go value such as string, []any or map[string]any,
+	// which can be navigated in JavaScript.
+	Export() any
+}
+
+// export returns v.Export(), or nil when v is nil such as a skipped field.
+func export(v TValue) any {
+	if v == nil {
+		return nil
+	}
+	return v.Export()
+}
+
+// toJS replaces bytes in v exported by [TValue.Export] with ArrayBuffer of rt.
+// v is returned as is when rt is nil such as out of k6.
+func toJS(rt *sobek.Runtime, v any) any {
+	if rt == nil {
+		return v
+	}
+	switch v := v.(type) {
+	case []byte:
+		return rt.NewArrayBuffer(v)
+	case []any:
+		for i, e := range v {
+			v[i] = toJS(rt, e)
+		}
+	case map[string]any:
+		for k, e := range v {
+			v[k] = toJS(rt, e)
+		}
+	}
+	return v
 }
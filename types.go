@@ -1,11 +1,401 @@
 package thrift
 
-type TTypes struct {}
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/grafana/sobek"
+	"go.k6.io/k6/js/modules"
+)
+
+// TTypes is `k6/x/thrift/ttypes` module, which creates values of Thrift types in JavaScript.
+// This is stateless, so the same instance is shared by all VUs.
+type TTypes struct{}
+
+var (
+	_ modules.Module   = (*TTypes)(nil)
+	_ modules.Instance = (*TTypes)(nil)
+)
+
+// maxSafeInteger is Number.MAX_SAFE_INTEGER in JavaScript.
+const maxSafeInteger = 1<<53 - 1
+
+// ttypeNames is thrift.TType exported as named constants such as `ttypes.STRING`,
+// which are given to constructors of containers.
+var ttypeNames = map[string]thrift.TType{
+	"BOOL":   thrift.BOOL,
+	"BYTE":   thrift.BYTE,
+	"I08":    thrift.I08,
+	"I16":    thrift.I16,
+	"I32":    thrift.I32,
+	"I64":    thrift.I64,
+	"DOUBLE": thrift.DOUBLE,
+	"STRING": thrift.STRING,
+	// binary is string on the wire
+	"BINARY": thrift.STRING,
+	"STRUCT": thrift.STRUCT,
+	"MAP":    thrift.MAP,
+	"SET":    thrift.SET,
+	"LIST":   thrift.LIST,
+	"UUID":   thrift.UUID,
+}
+
+func (t *TTypes) NewModuleInstance(modules.VU) modules.Instance {
+	return t
+}
+
+func (t *TTypes) Exports() modules.Exports {
+	named := map[string]any{
+		"newTString":           t.NewTString,
+		"newTBool":             t.NewTBool,
+		"newTEnum":             t.NewTEnum,
+		"newTByte":             t.NewTByte,
+		"newTI16":              t.NewTI16,
+		"newTI32":              t.NewTI32,
+		"newTI64":              t.NewTI64,
+		"newTDouble":           t.NewTDouble,
+		"newTBinary":           t.NewTBinary,
+		"newTBinaryFromHex":    t.NewTBinaryFromHex,
+		"newTBinaryFromBase64": t.NewTBinaryFromBase64,
+		"newTUUID":             t.NewTUUID,
+		"toHex":                t.ToHex,
+		"toBase64":             t.ToBase64,
+		"newTList":             t.NewTList,
+		"newTSet":              t.NewTSet,
+		"newTMap":              t.NewTMap,
+		"newTStruct":           t.NewTStruct,
+		"newTStructField":      t.NewTStructField,
+		"newTRequest":          t.NewTRequest,
+	}
+	for name, ttype := range ttypeNames {
+		named[name] = ttype
+	}
+	return modules.Exports{Named: named}
+}
 
 func (*TTypes) NewTString(v string) TString {
 	return NewTstring(v)
 }
 
+func (*TTypes) NewTBool(v bool) TBool {
+	return NewTBool(v)
+}
+
+func (*TTypes) NewTEnum(v int32) TEnum {
+	return NewTEnum(v)
+}
+
+// NewTByte creates [TByte], which throws an error when v is out of range of i8.
+func (*TTypes) NewTByte(v any) (TByte, error) {
+	i, err := integerOf("byte", v, math.MinInt8, math.MaxInt8)
+	if err != nil {
+		return TByte{}, err
+	}
+	return NewTByte(int8(i)), nil
+}
+
+func (*TTypes) NewTI16(v any) (TI16, error) {
+	i, err := integerOf("i16", v, math.MinInt16, math.MaxInt16)
+	if err != nil {
+		return TI16{}, err
+	}
+	return NewTI16(int16(i)), nil
+}
+
+func (*TTypes) NewTI32(v any) (TI32, error) {
+	i, err := integerOf("i32", v, math.MinInt32, math.MaxInt32)
+	if err != nil {
+		return TI32{}, err
+	}
+	return NewTI32(int32(i)), nil
+}
+
+// integerOf returns v as an integer of type name in range of minValue and maxValue. Numbers with fraction are exported
+// as float64, which are rejected instead of being truncated.
+func integerOf(name string, v any, minValue, maxValue int64) (int64, error) {
+	i, ok := v.(int64)
+	if !ok {
+		return 0, fmt.Errorf("%s must be an integer, but was %v", name, v)
+	}
+	if i < minValue || i > maxValue {
+		return 0, fmt.Errorf("%s must be in range of %s, but was %d", name, name, i)
+	}
+	return i, nil
+}
+
+// NewTI64 creates [TI64] from BigInt, decimal string or number.
+// Number must be a safe integer, since larger numbers have already lost precision.
+//
+//	ttypes.newTI64(9007199254740993n)
+//	ttypes.newTI64("9007199254740993")
+func (*TTypes) NewTI64(v any) (TI64, error) {
+	switch v := v.(type) {
+	case int64:
+		if v < -maxSafeInteger || v > maxSafeInteger {
+			return TI64{}, fmt.Errorf("i64 number must be a safe integer, but was %d. Use BigInt or string instead", v)
+		}
+		return NewTI64(v), nil
+	case float64:
+		return TI64{}, fmt.Errorf("i64 number must be a safe integer, but was %v. Use BigInt or string instead", v)
+	case *big.Int:
+		if !v.IsInt64() {
+			return TI64{}, fmt.Errorf("i64 must be in range of i64, but was %s", v)
+		}
+		return NewTI64(v.Int64()), nil
+	case string:
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return TI64{}, fmt.Errorf("i64 must be a decimal string in range of i64, but was %q", v)
+		}
+		return NewTI64(i), nil
+	default:
+		return TI64{}, fmt.Errorf("i64 must be an integer, BigInt or decimal string, but was %v", v)
+	}
+}
+
+func (*TTypes) NewTDouble(v float64) TDouble {
+	return NewTDouble(v)
+}
+
+// NewTBinary creates [TBinary] with a copy of v, which is ArrayBuffer, Uint8Array or an array of bytes.
+func (*TTypes) NewTBinary(v any) (TBinary, error) {
+	b, err := bytesOf(v)
+	if err != nil {
+		return TBinary{}, err
+	}
+	return NewTBinary(b), nil
+}
+
+// NewTBinaryFromHex creates [TBinary] from a hex string such as "cafe".
+func (*TTypes) NewTBinaryFromHex(v string) (TBinary, error) {
+	b, err := hex.DecodeString(v)
+	if err != nil {
+		return TBinary{}, fmt.Errorf("invalid hex string %q. %w", v, err)
+	}
+	return NewTBinary(b), nil
+}
+
+// NewTBinaryFromBase64 creates [TBinary] from a standard base64 string with padding.
+func (*TTypes) NewTBinaryFromBase64(v string) (TBinary, error) {
+	b, err := base64.StdEncoding.DecodeString(v)
+	if err != nil {
+		return TBinary{}, fmt.Errorf("invalid base64 string %q. %w", v, err)
+	}
+	return NewTBinary(b), nil
+}
+
+// NewTUUID creates [TUUID] from canonical form such as "6ba7b810-9dad-11d1-80b4-00c04fd430c8".
+func (*TTypes) NewTUUID(v string) (TUUID, error) {
+	u, err := thrift.ParseTuuid(v)
+	if err != nil {
+		return TUUID{}, fmt.Errorf("invalid uuid %q. %w", v, err)
+	}
+	return NewTUUID(u), nil
+}
+
+// ToHex returns v as a hex string, which is ArrayBuffer or Uint8Array such as binary in a response.
+func (*TTypes) ToHex(v any) (string, error) {
+	b, err := bytesOf(v)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// ToBase64 returns v as a standard base64 string, which is ArrayBuffer or Uint8Array such as binary in a response.
+func (*TTypes) ToBase64(v any) (string, error) {
+	b, err := bytesOf(v)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// NewTList creates [TList] of elemType with values. This is `ttypes.newTList(ttypes.STRING, [...])` in JavaScript.
+func (*TTypes) NewTList(elemType thrift.TType, values []TValue) (*TList, error) {
+	for i, v := range values {
+		if err := checkTType(fmt.Sprintf("list element %d", i), elemType, v); err != nil {
+			return nil, err
+		}
+	}
+	if values == nil {
+		values = []TValue{}
+	}
+	return NewTList(&values, elemType), nil
+}
+
+// NewTSet creates [TSet] of elemType with values, which throws an error when values have duplicates.
+// This is `ttypes.newTSet(ttypes.STRING, [...])` in JavaScript.
+func (*TTypes) NewTSet(elemType thrift.TType, values []TValue) (*TSet, error) {
+	for i, v := range values {
+		if err := checkTType(fmt.Sprintf("set element %d", i), elemType, v); err != nil {
+			return nil, err
+		}
+	}
+	if values == nil {
+		values = []TValue{}
+	}
+	set := NewTSet(&values, elemType)
+	if i, j := set.duplicated(); i >= 0 {
+		return nil, fmt.Errorf("set elements %d and %d are duplicated", i, j)
+	}
+	return set, nil
+}
+
+// NewTMap creates [TMap] of keyType and valueType with entries, which is a JavaScript Map or an array of [key, value] pairs.
+// Object can't be used, since its keys are converted to strings.
+//
+//	ttypes.newTMap(ttypes.STRING, ttypes.BOOL, new Map([[ttypes.newTString("key"), ttypes.newTBool(true)]]))
+//	ttypes.newTMap(ttypes.STRING, ttypes.BOOL, [[ttypes.newTString("key"), ttypes.newTBool(true)]])
+func (*TTypes) NewTMap(keyType, valueType thrift.TType, entries any) (*TMap, error) {
+	pairs, err := toPairs("map entries", entries)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[TValue]TValue, len(pairs))
+	for i, pair := range pairs {
+		k, ok := pair[0].(TValue)
+		if !ok {
+			return nil, fmt.Errorf("map key %d must be ttypes, but was %T", i, pair[0])
+		}
+		v, ok := pair[1].(TValue)
+		if !ok {
+			return nil, fmt.Errorf("map value %d must be ttypes, but was %T", i, pair[1])
+		}
+		if err := checkTType(fmt.Sprintf("map key %d", i), keyType, k); err != nil {
+			return nil, err
+		}
+		if err := checkTType(fmt.Sprintf("map value %d", i), valueType, v); err != nil {
+			return nil, err
+		}
+		m[k] = v
+	}
+	return NewTMap(keyType, valueType, &m), nil
+}
+
+// NewTStruct creates [TStruct] with fields, which is an object keyed by field IDs or an array of [field, value] pairs.
+// Field of the pair is a field ID or [TStructField] created by `ttypes.newTStructField(id, name)`.
+//
+//	ttypes.newTStruct({ 1: ttypes.newTString("bar") })
+//	ttypes.newTStruct([[ttypes.newTStructField(1, "bar"), ttypes.newTString("bar")]])
+func (*TTypes) NewTStruct(fields any) (*TStruct, error) {
+	value := make(map[TStructField]TValue)
+	ids := make(map[int16]bool)
+	put := func(f TStructField, v any) error {
+		tv, ok := v.(TValue)
+		if !ok || tv == nil {
+			return fmt.Errorf("struct field %d must be ttypes, but was %T", f.id, v)
+		}
+		if ids[f.id] {
+			return fmt.Errorf("struct field %d is duplicated", f.id)
+		}
+		ids[f.id] = true
+		value[f] = tv
+		return nil
+	}
+
+	if obj, ok := fields.(map[string]any); ok {
+		for k, v := range obj {
+			id, err := strconv.ParseInt(k, 10, 16)
+			if err != nil {
+				return nil, fmt.Errorf("struct field ID must be int16, but was %q", k)
+			}
+			if err := put(TStructField{id: int16(id)}, v); err != nil {
+				return nil, err
+			}
+		}
+		return NewTStruct(&value), nil
+	}
+
+	pairs, err := toPairs("struct fields", fields)
+	if err != nil {
+		return nil, err
+	}
+	for i, pair := range pairs {
+		var f TStructField
+		switch k := pair[0].(type) {
+		case *TStructField:
+			f = *k
+		case int64:
+			if int64(int16(k)) != k {
+				return nil, fmt.Errorf("struct field ID must be int16, but was %d", k)
+			}
+			f = TStructField{id: int16(k)}
+		default:
+			return nil, fmt.Errorf("struct field %d must be a field ID or ttypes field, but was %T", i, pair[0])
+		}
+		if err := put(f, pair[1]); err != nil {
+			return nil, err
+		}
+	}
+	return NewTStruct(&value), nil
+}
+
+func (*TTypes) NewTStructField(id int16, name string) *TStructField {
+	return NewTStructField(id, name)
+}
+
 func (*TTypes) NewTRequest(v *map[int16]TValue) *TRequest {
 	return NewTRequestWithValue(v)
 }
+
+// toPairs returns v exported from JavaScript Map or an array of [key, value] pairs as pairs.
+func toPairs(what string, v any) ([][2]any, error) {
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case [][2]any:
+		// JavaScript Map
+		return v, nil
+	case []any:
+		pairs := make([][2]any, 0, len(v))
+		for i, e := range v {
+			pair, ok := e.([]any)
+			if !ok || len(pair) != 2 {
+				return nil, fmt.Errorf("%s %d must be [key, value] pair, but was %v", what, i, e)
+			}
+			pairs = append(pairs, [2]any{pair[0], pair[1]})
+		}
+		return pairs, nil
+	default:
+		return nil, fmt.Errorf("%s must be Map or an array of [key, value] pairs, but was %T", what, v)
+	}
+}
+
+// bytesOf returns bytes of v exported from ArrayBuffer, Uint8Array or an array of bytes.
+func bytesOf(v any) ([]byte, error) {
+	switch v := v.(type) {
+	case sobek.ArrayBuffer:
+		return v.Bytes(), nil
+	case []byte:
+		return v, nil
+	case []any:
+		b := make([]byte, 0, len(v))
+		for i, e := range v {
+			n, ok := e.(int64)
+			if !ok || n < 0 || n > math.MaxUint8 {
+				return nil, fmt.Errorf("byte %d must be an integer from 0 to 255, but was %v", i, e)
+			}
+			b = append(b, byte(n))
+		}
+		return b, nil
+	default:
+		return nil, fmt.Errorf("binary must be ArrayBuffer, Uint8Array or an array of bytes, but was %T", v)
+	}
+}
+
+// checkTType returns an error when v is nil or not a value of ttype.
+func checkTType(what string, ttype thrift.TType, v TValue) error {
+	if v == nil {
+		return fmt.Errorf("%s must be ttypes, but was null", what)
+	}
+	if v.TType() != ttype {
+		return fmt.Errorf("%s must be %s, but was %s", what, ttype, v.TType())
+	}
+	return nil
+}
@@ -0,0 +1,300 @@
+package thrift
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/grafana/sobek"
+	"go.k6.io/k6/js/modulestest"
+)
+
+func setupTTypes(t *testing.T) *sobek.Runtime {
+	rt := modulestest.NewRuntime(t)
+	m := new(TTypes).NewModuleInstance(rt.VU)
+	checkError(t, rt.VU.Runtime().Set("ttypes", m.Exports().Named))
+	return rt.VU.Runtime()
+}
+
+func TestTTypes_constants(t *testing.T) {
+	// prepare
+	rt := setupTTypes(t)
+
+	// do
+	v, err := rt.RunString(`[ttypes.BOOL, ttypes.I32, ttypes.STRING, ttypes.STRUCT, ttypes.MAP, ttypes.SET, ttypes.LIST]`)
+	checkError(t, err)
+
+	// verify
+	var actual []int
+	checkError(t, rt.ExportTo(v, &actual))
+	expected := []int{thrift.BOOL, thrift.I32, thrift.STRING, thrift.STRUCT, thrift.MAP, thrift.SET, thrift.LIST}
+	assert(t, "len", len(actual), len(expected))
+	for i := range expected {
+		assert(t, "constant", actual[i], expected[i])
+	}
+}
+
+func TestTTypes_scalars(t *testing.T) {
+	// prepare
+	rt := setupTTypes(t)
+
+	// do
+	v, err := rt.RunString(`[ttypes.newTString("foo"), ttypes.newTBool(true), ttypes.newTEnum(2)]`)
+	checkError(t, err)
+
+	// verify
+	var actual []TValue
+	checkError(t, rt.ExportTo(v, &actual))
+	var s, b, e TValue = NewTstring("foo"), NewTBool(true), NewTEnum(2)
+	assertTrue(t, "string", actual[0].Equals(&s))
+	assertTrue(t, "bool", actual[1].Equals(&b))
+	assertTrue(t, "enum", actual[2].Equals(&e))
+}
+
+func TestTTypes_numbers(t *testing.T) {
+	// prepare
+	rt := setupTTypes(t)
+
+	// do
+	v, err := rt.RunString(`[
+		ttypes.newTByte(-128),
+		ttypes.newTI16(32767),
+		ttypes.newTI32(-2147483648),
+		ttypes.newTI64(9007199254740991),
+		ttypes.newTI64(9223372036854775807n),
+		ttypes.newTI64("-9223372036854775808"),
+		ttypes.newTDouble(0.5),
+	]`)
+	checkError(t, err)
+
+	// verify
+	var actual []TValue
+	checkError(t, rt.ExportTo(v, &actual))
+	expected := []TValue{
+		NewTByte(-128),
+		NewTI16(32767),
+		NewTI32(-2147483648),
+		NewTI64(9007199254740991),
+		NewTI64(9223372036854775807),
+		NewTI64(-9223372036854775808),
+		NewTDouble(0.5),
+	}
+	assert(t, "len", len(actual), len(expected))
+	for i := range expected {
+		assertTrue(t, fmt.Sprintf("value %d", i), actual[i].Equals(&expected[i]))
+	}
+}
+
+func TestTTypes_i64ToJS(t *testing.T) {
+	// prepare
+	rt := setupTTypes(t)
+
+	// do
+	v, err := rt.RunString(`
+		const i = ttypes.newTI64("9007199254740993").export();
+		typeof i === "bigint" && i === 9007199254740993n && String(i) === "9007199254740993"
+	`)
+	checkError(t, err)
+
+	// verify
+	assertTrue(t, "lossless BigInt", v.ToBoolean())
+}
+
+func TestTTypes_binary(t *testing.T) {
+	cases := map[string]string{
+		"ArrayBuffer": `ttypes.newTBinary(new Uint8Array([0xca, 0xfe]).buffer)`,
+		"Uint8Array":  `ttypes.newTBinary(new Uint8Array([0xca, 0xfe]))`,
+		"array":       `ttypes.newTBinary([0xca, 0xfe])`,
+		"hex":         `ttypes.newTBinaryFromHex("cafe")`,
+		"base64":      `ttypes.newTBinaryFromBase64("yv4=")`,
+	}
+
+	for title, script := range cases {
+		t.Run(title, func(t *testing.T) {
+			// prepare
+			rt := setupTTypes(t)
+
+			// do
+			v, err := rt.RunString(script)
+			checkError(t, err)
+
+			// verify
+			actual, ok := v.Export().(TBinary)
+			assertTrue(t, "binary", ok)
+			assert(t, "hex", actual.Hex(), "cafe")
+		})
+	}
+}
+
+func TestTTypes_binaryHelpers(t *testing.T) {
+	// prepare
+	rt := setupTTypes(t)
+
+	// do
+	v, err := rt.RunString(`
+		const b = ttypes.newTBinaryFromHex("cafe");
+		const buf = new Uint8Array([0xca, 0xfe]).buffer;
+		[b.hex(), b.base64(), ttypes.toHex(buf), ttypes.toBase64(new Uint8Array(buf))]
+	`)
+	checkError(t, err)
+
+	// verify
+	var actual []string
+	checkError(t, rt.ExportTo(v, &actual))
+	assert(t, "hex", actual[0], "cafe")
+	assert(t, "base64", actual[1], "yv4=")
+	assert(t, "toHex", actual[2], "cafe")
+	assert(t, "toBase64", actual[3], "yv4=")
+}
+
+func TestTTypes_newTUUID(t *testing.T) {
+	// prepare
+	rt := setupTTypes(t)
+
+	// do
+	v, err := rt.RunString(`ttypes.newTUUID("6ba7b810-9dad-11d1-80b4-00c04fd430c8")`)
+	checkError(t, err)
+
+	// verify
+	actual, ok := v.Export().(TUUID)
+	assertTrue(t, "uuid", ok)
+	assert(t, "value", actual.Export().(string), "6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+}
+
+func TestTTypes_newTList(t *testing.T) {
+	// prepare
+	rt := setupTTypes(t)
+
+	// do
+	v, err := rt.RunString(`ttypes.newTList(ttypes.STRING, [ttypes.newTString("a"), ttypes.newTString("b")])`)
+	checkError(t, err)
+
+	// verify
+	actual, ok := v.Export().(*TList)
+	assertTrue(t, "list", ok)
+	var expected TValue = NewTList(&[]TValue{NewTstring("a"), NewTstring("b")}, thrift.STRING)
+	assertTrue(t, "equals", actual.Equals(&expected))
+	assert(t, "valueType", actual.valueType, thrift.STRING)
+}
+
+func TestTTypes_newTSet(t *testing.T) {
+	// prepare
+	rt := setupTTypes(t)
+
+	// do
+	v, err := rt.RunString(`ttypes.newTSet(ttypes.I32, [ttypes.newTI32(1), ttypes.newTI32(2)])`)
+	checkError(t, err)
+
+	// verify
+	actual, ok := v.Export().(*TSet)
+	assertTrue(t, "set", ok)
+	var expected TValue = NewTSet(&[]TValue{NewTI32(2), NewTI32(1)}, thrift.I32)
+	assertTrue(t, "equals", actual.Equals(&expected))
+	assert(t, "valueType", actual.valueType, thrift.I32)
+}
+
+func TestTTypes_newTMap(t *testing.T) {
+	cases := map[string]string{
+		"Map":   `ttypes.newTMap(ttypes.STRING, ttypes.BOOL, new Map([[ttypes.newTString("a"), ttypes.newTBool(true)]]))`,
+		"pairs": `ttypes.newTMap(ttypes.STRING, ttypes.BOOL, [[ttypes.newTString("a"), ttypes.newTBool(true)]])`,
+	}
+
+	for title, script := range cases {
+		t.Run(title, func(t *testing.T) {
+			// prepare
+			rt := setupTTypes(t)
+
+			// do
+			v, err := rt.RunString(script)
+			checkError(t, err)
+
+			// verify
+			actual, ok := v.Export().(*TMap)
+			assertTrue(t, "map", ok)
+			var expected TValue = NewTMap(thrift.STRING, thrift.BOOL, &map[TValue]TValue{NewTstring("a"): NewTBool(true)})
+			assertTrue(t, "equals", actual.Equals(&expected))
+			assert(t, "keyType", actual.keyType, thrift.STRING)
+			assert(t, "valueType", actual.valueType, thrift.BOOL)
+		})
+	}
+}
+
+func TestTTypes_newTStruct(t *testing.T) {
+	cases := map[string]struct {
+		script    string
+		fieldName string
+	}{
+		"object": {`ttypes.newTStruct({ 1: ttypes.newTString("a"), 2: ttypes.newTEnum(3) })`, ""},
+		"pairs": {
+			`ttypes.newTStruct([[ttypes.newTStructField(1, "name"), ttypes.newTString("a")], [2, ttypes.newTEnum(3)]])`,
+			"name",
+		},
+	}
+
+	for title, c := range cases {
+		t.Run(title, func(t *testing.T) {
+			// prepare
+			rt := setupTTypes(t)
+
+			// do
+			v, err := rt.RunString(c.script)
+			checkError(t, err)
+
+			// verify
+			actual, ok := v.Export().(*TStruct)
+			assertTrue(t, "struct", ok)
+			var expected TValue = NewTStruct(&map[TStructField]TValue{
+				*NewTStructField(1, c.fieldName): NewTstring("a"),
+				*NewTStructField(2, ""):          NewTEnum(3),
+			})
+			assertTrue(t, "equals", actual.Equals(&expected))
+		})
+	}
+}
+
+func TestTTypes_invalid(t *testing.T) {
+	cases := map[string]string{
+		"byte out of range": `ttypes.newTByte(128)`,
+		"i16 out of range":  `ttypes.newTI16(-32769)`,
+		"i32 out of range":  `ttypes.newTI32(2147483648)`,
+		"byte fraction":     `ttypes.newTByte(1.7)`,
+		"i16 fraction":      `ttypes.newTI16(-0.5)`,
+		"i32 fraction":      `ttypes.newTI32(2.5)`,
+		"i32 string":        `ttypes.newTI32("1")`,
+		"i64 unsafe number": `ttypes.newTI64(9007199254740993)`,
+		"i64 fraction":      `ttypes.newTI64(0.5)`,
+		"i64 out of range":  `ttypes.newTI64(9223372036854775808n)`,
+		"i64 invalid":       `ttypes.newTI64("1e3")`,
+		"binary string":     `ttypes.newTBinary("cafe")`,
+		"binary byte":       `ttypes.newTBinary([256])`,
+		"binary hex":        `ttypes.newTBinaryFromHex("xyz")`,
+		"binary base64":     `ttypes.newTBinaryFromBase64("!")`,
+		"uuid":              `ttypes.newTUUID("foo")`,
+		"list element type": `ttypes.newTList(ttypes.STRING, [ttypes.newTBool(true)])`,
+		"list null element": `ttypes.newTList(ttypes.STRING, [null])`,
+		"set element type":  `ttypes.newTSet(ttypes.I32, [ttypes.newTString("a")])`,
+		"set duplicated":    `ttypes.newTSet(ttypes.STRING, [ttypes.newTString("a"), ttypes.newTString("a")])`,
+		"map key type":      `ttypes.newTMap(ttypes.STRING, ttypes.BOOL, [[ttypes.newTBool(true), ttypes.newTBool(true)]])`,
+		"map value type":    `ttypes.newTMap(ttypes.STRING, ttypes.BOOL, [[ttypes.newTString("a"), ttypes.newTString("a")]])`,
+		"map raw key":       `ttypes.newTMap(ttypes.STRING, ttypes.BOOL, [["a", ttypes.newTBool(true)]])`,
+		"map object":        `ttypes.newTMap(ttypes.STRING, ttypes.BOOL, { a: ttypes.newTBool(true) })`,
+		"map pair":          `ttypes.newTMap(ttypes.STRING, ttypes.BOOL, [[ttypes.newTString("a")]])`,
+		"struct field ID":   `ttypes.newTStruct({ foo: ttypes.newTString("a") })`,
+		"struct raw value":  `ttypes.newTStruct({ 1: "a" })`,
+		"struct duplicated": `ttypes.newTStruct([[1, ttypes.newTString("a")], [ttypes.newTStructField(1, "name"), ttypes.newTString("b")]])`,
+		"struct pair":       `ttypes.newTStruct([["foo", ttypes.newTString("a")]])`,
+	}
+
+	for title, script := range cases {
+		t.Run(title, func(t *testing.T) {
+			// prepare
+			rt := setupTTypes(t)
+
+			// do
+			_, err := rt.RunString(script)
+
+			// verify
+			assertTrue(t, "error expected", err != nil)
+		})
+	}
+}